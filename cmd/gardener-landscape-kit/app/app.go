@@ -12,8 +12,10 @@ import (
 	"k8s.io/component-base/version/verflag"
 
 	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd/bumpbase"
 	"github.com/gardener/gardener-landscape-kit/pkg/cmd/generate"
 	"github.com/gardener/gardener-landscape-kit/pkg/cmd/resolveocm"
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd/secrets"
 )
 
 // Name is a const for the name of this component.
@@ -47,6 +49,8 @@ func NewCommand() *cobra.Command {
 	for _, subcommand := range []*cobra.Command{
 		generate.NewCommand(opts),
 		resolveocm.NewCommand(opts),
+		bumpbase.NewCommand(opts),
+		secrets.NewCommand(opts),
 	} {
 		cmd.AddCommand(subcommand)
 	}
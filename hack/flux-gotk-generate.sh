@@ -9,7 +9,7 @@ alias flux=$FLUX_CLI
 echo "> Generating Flux components"
 flux install \
   --export \
-  > pkg/components/flux/templates/landscape/gotk-components.yaml
+  > pkg/components/flux/templates/base/gotk-components.yaml
 flux create secret git flux-system \
   --url="https://github.com/<org>/<repo>" \
   --username="<username>" \
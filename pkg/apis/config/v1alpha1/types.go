@@ -5,7 +5,10 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -17,6 +20,107 @@ type LandscapeKitConfiguration struct {
 	// OCM is the configuration for the OCM version processing.
 	// +optional
 	OCM *OCMConfig `json:"ocm,omitempty"`
+	// Components contains the configuration of individual components.
+	// +optional
+	Components []ComponentConfig `json:"components,omitempty"`
+	// Variables are landscape-wide variables, e.g. the domain or region of the landscape. They are substituted by Flux in all
+	// manifests applied by the generated Flux Kustomizations, where they can be referenced as ${name}.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+	// BaseSource configures the landscape to consume the base directory from a separate, pinned Git repository or OCI artifact.
+	// If set, the landscape directory is expected to be the root of the landscape Git repository.
+	// +optional
+	BaseSource *BaseSource `json:"baseSource,omitempty"`
+	// FileLayout defines how generated manifests consisting of multiple objects are written. Defaults to Bundle.
+	// Changing the layout migrates existing files including their modifications, the files of the previous layout are reported as orphaned.
+	// +optional
+	FileLayout FileLayout `json:"fileLayout,omitempty"`
+	// Namespaces configures the namespaces generated at the beginning of the rollout. The settings are merged with the namespaces
+	// declared by the components and take precedence over them. Namespaces that are not declared by any component are generated as well.
+	// +optional
+	Namespaces []NamespaceConfig `json:"namespaces,omitempty"`
+	// Secrets configures the scaffolding of the secrets required by the components, e.g. the credentials of DNS providers and backups.
+	// The secrets are stored encrypted with SOPS in the landscape directory. If not set, no secrets are generated.
+	// +optional
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+}
+
+// SecretsConfig contains the configuration of the secrets required by the components.
+type SecretsConfig struct {
+	// AgeRecipients are the public keys of the age key pairs the secrets are encrypted for with SOPS.
+	AgeRecipients []string `json:"ageRecipients"`
+}
+
+// NamespaceConfig contains the configuration of a single namespace.
+type NamespaceConfig struct {
+	// Name is the name of the namespace.
+	Name string `json:"name"`
+	// PodSecurityLevel is the Pod Security Admission level enforced in the namespace. One of privileged, baseline or restricted.
+	// +optional
+	PodSecurityLevel string `json:"podSecurityLevel,omitempty"`
+	// Labels are additional labels of the namespace.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are additional annotations of the namespace.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// FileLayout defines how generated manifests consisting of multiple objects are written.
+type FileLayout string
+
+const (
+	// FileLayoutBundle writes all objects of a manifest into a single multi-document file.
+	FileLayoutBundle FileLayout = "Bundle"
+	// FileLayoutSplit writes each object of a manifest into its own file named by its kind and name.
+	FileLayoutSplit FileLayout = "Split"
+)
+
+// BaseSource references a Git repository or an OCI artifact containing the base directory.
+type BaseSource struct {
+	// URL is the URL of the Git repository. URLs with the oci:// scheme reference an OCI artifact instead, e.g. pushed with
+	// `flux push artifact`.
+	URL string `json:"url"`
+	// Ref pins the revision of the Git repository or OCI artifact.
+	Ref BaseSourceRef `json:"ref"`
+	// Path is the path of the base directory within the Git repository or OCI artifact. Defaults to the root.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// SecretRefName is the name of the secret in the flux-system namespace containing the credentials for the Git repository or
+	// OCI registry.
+	// +optional
+	SecretRefName string `json:"secretRefName,omitempty"`
+}
+
+// IsOCI returns whether the base source references an OCI artifact.
+func (s *BaseSource) IsOCI() bool {
+	return strings.HasPrefix(s.URL, "oci://")
+}
+
+// BaseSourceRef pins the revision of the base source. Git repositories are pinned by tag or commit, OCI artifacts by tag or digest.
+type BaseSourceRef struct {
+	// Tag is the Git or OCI tag to check out.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+	// Commit is the Git commit SHA to check out. It takes precedence over the tag.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+	// Digest is the digest of the OCI artifact to pull. It takes precedence over the tag.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// ComponentConfig contains the configuration of a single component.
+type ComponentConfig struct {
+	// Name is the name of the component.
+	Name string `json:"name"`
+	// Config is the component specific configuration. Its format is defined by the respective component.
+	// +optional
+	Config *runtime.RawExtension `json:"config,omitempty"`
+	// Features is the list of optional features of the component that are enabled in the landscape.
+	// The available features are defined by the respective component.
+	// +optional
+	Features []string `json:"features,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
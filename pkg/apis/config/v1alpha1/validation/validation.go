@@ -5,9 +5,17 @@
 package validation
 
 import (
+	"maps"
 	"net/url"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
@@ -21,6 +29,150 @@ func ValidateLandscapeKitConfiguration(conf *configv1alpha1.LandscapeKitConfigur
 		allErrs = append(allErrs, ValidateOCMConfig(conf.OCM, field.NewPath("ocm"))...)
 	}
 
+	allErrs = append(allErrs, validateComponentConfigs(conf.Components, field.NewPath("components"))...)
+	allErrs = append(allErrs, validateVariables(conf.Variables, field.NewPath("variables"))...)
+
+	if conf.BaseSource != nil {
+		allErrs = append(allErrs, validateBaseSource(conf.BaseSource, field.NewPath("baseSource"))...)
+	}
+
+	allErrs = append(allErrs, validateNamespaceConfigs(conf.Namespaces, field.NewPath("namespaces"))...)
+
+	if conf.Secrets != nil {
+		allErrs = append(allErrs, validateSecretsConfig(conf.Secrets, field.NewPath("secrets"))...)
+	}
+
+	if conf.FileLayout != "" && !slices.Contains(supportedFileLayouts, conf.FileLayout) {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("fileLayout"), conf.FileLayout, supportedFileLayouts))
+	}
+
+	return allErrs
+}
+
+var supportedPodSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+func validateNamespaceConfigs(confs []configv1alpha1.NamespaceConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.New[string]()
+	for i, conf := range confs {
+		idxPath := fldPath.Index(i)
+		if conf.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "namespace name is required"))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(conf.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), conf.Name, msg))
+			}
+			if names.Has(conf.Name) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), conf.Name))
+			}
+			names.Insert(conf.Name)
+		}
+
+		if conf.PodSecurityLevel != "" && !slices.Contains(supportedPodSecurityLevels, conf.PodSecurityLevel) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("podSecurityLevel"), conf.PodSecurityLevel, supportedPodSecurityLevels))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabels(conf.Labels, idxPath.Child("labels"))...)
+		allErrs = append(allErrs, apivalidation.ValidateAnnotations(conf.Annotations, idxPath.Child("annotations"))...)
+	}
+
+	return allErrs
+}
+
+var supportedFileLayouts = []configv1alpha1.FileLayout{configv1alpha1.FileLayoutBundle, configv1alpha1.FileLayoutSplit}
+
+func validateBaseSource(source *configv1alpha1.BaseSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if source.URL == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("url"), "base source URL is required"))
+	}
+
+	if source.IsOCI() {
+		if source.Ref.Tag == "" && source.Ref.Digest == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("ref"), "tag or digest is required to pin the OCI base source"))
+		}
+		if source.Ref.Commit != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ref", "commit"), "commit is only supported for Git base sources"))
+		}
+	} else {
+		if source.Ref.Tag == "" && source.Ref.Commit == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("ref"), "tag or commit is required to pin the Git base source"))
+		}
+		if source.Ref.Digest != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ref", "digest"), "digest is only supported for OCI base sources"))
+		}
+	}
+
+	if path.IsAbs(source.Path) || strings.HasPrefix(path.Clean(source.Path), "..") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), source.Path, "path must be relative to the repository root"))
+	}
+
+	return allErrs
+}
+
+// ageRecipientRegex matches the Bech32 encoded public keys of age key pairs.
+var ageRecipientRegex = regexp.MustCompile(`^age1[02-9ac-hj-np-z]{58}$`)
+
+func validateSecretsConfig(conf *configv1alpha1.SecretsConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(conf.AgeRecipients) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("ageRecipients"), "at least one age recipient is required to encrypt the secrets"))
+	}
+	for i, recipient := range conf.AgeRecipients {
+		if !ageRecipientRegex.MatchString(recipient) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ageRecipients").Index(i), recipient, "must be the public key of an age key pair"))
+		}
+	}
+
+	return allErrs
+}
+
+// variableNameRegex matches the variable names that are supported by the Flux post-build substitution.
+var variableNameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+func validateVariables(variables map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if !variableNameRegex.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), name, "variable name must consist of alphanumeric characters or '_' and must not start with a digit"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateComponentConfigs(confs []configv1alpha1.ComponentConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.New[string]()
+	for i, conf := range confs {
+		idxPath := fldPath.Index(i)
+		if conf.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "component name is required"))
+			continue
+		}
+		if names.Has(conf.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), conf.Name))
+		}
+		names.Insert(conf.Name)
+
+		features := sets.New[string]()
+		for j, feature := range conf.Features {
+			featurePath := idxPath.Child("features").Index(j)
+			if feature == "" {
+				allErrs = append(allErrs, field.Required(featurePath, "feature name is required"))
+				continue
+			}
+			if features.Has(feature) {
+				allErrs = append(allErrs, field.Duplicate(featurePath, feature))
+			}
+			features.Insert(feature)
+		}
+	}
+
 	return allErrs
 }
 
@@ -52,6 +52,196 @@ var _ = Describe("Validation", func() {
 			errList := validation.ValidateLandscapeKitConfiguration(conf)
 			Expect(errList).To(HaveLen(3))
 		})
+
+		It("should fail if component configs are missing names or are duplicated", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Components: []v1alpha1.ComponentConfig{
+					{Name: "flux"},
+					{Name: ""},
+					{Name: "flux"},
+				},
+			}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("components[1].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("components[2].name"),
+				})),
+			))
+		})
+
+		It("should fail if variable names are invalid", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Variables: map[string]string{
+					"domain":      "example.com",
+					"_region_1":   "eu",
+					"1st":         "invalid",
+					"with-hyphen": "invalid",
+				},
+			}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("variables[1st]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("variables[with-hyphen]"),
+				})),
+			))
+		})
+
+		It("should fail if the base source is invalid", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				BaseSource: &v1alpha1.BaseSource{
+					Ref:  v1alpha1.BaseSourceRef{Digest: "sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b"},
+					Path: "../base",
+				},
+			}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("baseSource.url"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("baseSource.ref"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("baseSource.ref.digest"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("baseSource.path"),
+				})),
+			))
+		})
+
+		It("should allow OCI base sources pinned by tag or digest", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				BaseSource: &v1alpha1.BaseSource{
+					URL: "oci://example.com/base",
+					Ref: v1alpha1.BaseSourceRef{Tag: "v1.0.0"},
+				},
+			}
+			Expect(validation.ValidateLandscapeKitConfiguration(conf)).To(BeEmpty())
+
+			conf.BaseSource.Ref = v1alpha1.BaseSourceRef{Commit: "4e2a5d1"}
+			Expect(validation.ValidateLandscapeKitConfiguration(conf)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("baseSource.ref"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("baseSource.ref.commit"),
+				})),
+			))
+		})
+
+		It("should fail if the file layout is unsupported", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{FileLayout: "Nested"}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("fileLayout"),
+				})),
+			))
+		})
+
+		It("should fail if namespace configs are invalid", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Namespaces: []v1alpha1.NamespaceConfig{
+					{Name: "garden", PodSecurityLevel: "restricted"},
+					{Name: "garden"},
+					{Name: "Invalid_Name", PodSecurityLevel: "strict"},
+					{Labels: map[string]string{"in valid": "true"}},
+				},
+			}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("namespaces[1].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("namespaces[2].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("namespaces[2].podSecurityLevel"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("namespaces[3].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("namespaces[3].labels"),
+				})),
+			))
+		})
+
+		It("should pass with valid age recipients", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Secrets: &v1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}},
+			}
+
+			Expect(validation.ValidateLandscapeKitConfiguration(conf)).To(BeEmpty())
+		})
+
+		It("should fail if the age recipients are missing or invalid", func() {
+			Expect(validation.ValidateLandscapeKitConfiguration(&v1alpha1.LandscapeKitConfiguration{Secrets: &v1alpha1.SecretsConfig{}})).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("secrets.ageRecipients"),
+				})),
+			))
+
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Secrets: &v1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", "ssh-ed25519 AAAA"}},
+			}
+			Expect(validation.ValidateLandscapeKitConfiguration(conf)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("secrets.ageRecipients[1]"),
+				})),
+			))
+		})
+
+		It("should fail if component features are empty or duplicated", func() {
+			conf := &v1alpha1.LandscapeKitConfiguration{
+				Components: []v1alpha1.ComponentConfig{
+					{Name: "flux", Features: []string{"ha", "", "ha", "debug"}},
+				},
+			}
+
+			errList := validation.ValidateLandscapeKitConfiguration(conf)
+			Expect(errList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("components[0].features[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("components[0].features[2]"),
+				})),
+			))
+		})
 	})
 
 	Describe("#ValidateOCMConfiguration", func() {
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bumpbase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	fluxcomponent "github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+)
+
+// NewCommand creates a new cobra.Command for running gardener-landscape-kit bump-base.
+func NewCommand(globalOpts *cmd.Options) *cobra.Command {
+	opts := &Options{Options: globalOpts}
+
+	cmd := &cobra.Command{
+		Use:   "bump-base",
+		Short: "Bumps the pinned version of the base source",
+		Long: "Pins the base source configured in the landscape kit configuration to the given tag, commit or digest. " +
+			"Both the configuration file and the generated GitRepository or OCIRepository of the base source in the landscape directory are updated.",
+
+		Example: `# Pin the base source to a new tag
+gardener-landscape-kit bump-base --config /path/to/config --landscape-dir /path/to/landscape/dir --tag v1.2.0
+
+# Pin an OCI base source to a digest
+gardener-landscape-kit bump-base --config /path/to/config --landscape-dir /path/to/landscape/dir --digest sha256:<digest>
+`,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			return run(cmd.Context(), opts)
+		},
+	}
+
+	opts.addFlags(cmd.Flags())
+
+	return cmd
+}
+
+func run(_ context.Context, opts *Options) error {
+	configData, err := setBaseSourceRef(opts.configData, opts.ref())
+	if err != nil {
+		return fmt.Errorf("failed updating base source in config file: %w", err)
+	}
+	if err := os.WriteFile(opts.configFilePath, configData, 0600); err != nil {
+		return fmt.Errorf("failed writing config file: %w", err)
+	}
+
+	opts.Config.BaseSource.Ref = opts.ref()
+	componentOpts := components.NewOptions("", nil, opts.LandscapeDir, opts.Config, nil, afero.Afero{Fs: afero.NewOsFs()}, opts.Log)
+	if err := fluxcomponent.WriteBaseSource(componentOpts); err != nil {
+		return fmt.Errorf("failed writing base source: %w", err)
+	}
+
+	opts.Log.Info("Bumped base source", "url", opts.Config.BaseSource.URL, "tag", opts.Tag, "commit", opts.Commit, "digest", opts.Digest)
+	return nil
+}
+
+// setBaseSourceRef replaces the ref of the base source in the given configuration file while keeping its formatting and comments.
+func setBaseSourceRef(configData []byte, baseSourceRef configv1alpha1.BaseSourceRef) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(configData, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, fmt.Errorf("config file is empty")
+	}
+
+	baseSource := mappingValue(document.Content[0], "baseSource")
+	if baseSource == nil {
+		return nil, fmt.Errorf("no base source configured")
+	}
+
+	ref := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range [][2]string{{"tag", baseSourceRef.Tag}, {"commit", baseSourceRef.Commit}, {"digest", baseSourceRef.Digest}} {
+		if field[1] != "" {
+			ref.Content = append(ref.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field[0]}, &yaml.Node{Kind: yaml.ScalarNode, Value: field[1]})
+		}
+	}
+	if existing := mappingValue(baseSource, "ref"); existing != nil {
+		ref.HeadComment, ref.LineComment, ref.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+		*existing = *ref
+	} else {
+		baseSource.Content = append(baseSource.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "ref"}, ref)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bumpbase
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+)
+
+var _ = Describe("Bump Base", func() {
+	Describe("#setBaseSourceRef", func() {
+		It("should replace the ref while keeping the comments and the order of the fields", func() {
+			configData := []byte(`apiVersion: landscape.config.gardener.cloud/v1alpha1
+kind: LandscapeKitConfiguration
+# The base is maintained by the platform team.
+baseSource:
+  url: https://example.com/base # pinned for all landscapes
+  ref: # bumped by bump-base
+    tag: v1.0.0
+  path: base
+variables:
+  domain: example.com
+`)
+
+			Expect(setBaseSourceRef(configData, configv1alpha1.BaseSourceRef{Commit: "4e2a5d1"})).To(Equal([]byte(`apiVersion: landscape.config.gardener.cloud/v1alpha1
+kind: LandscapeKitConfiguration
+# The base is maintained by the platform team.
+baseSource:
+  url: https://example.com/base # pinned for all landscapes
+  ref: # bumped by bump-base
+    commit: 4e2a5d1
+  path: base
+variables:
+  domain: example.com
+`)))
+		})
+
+		It("should add the ref if it is missing", func() {
+			configData := []byte(`baseSource:
+  url: oci://example.com/base
+`)
+
+			Expect(setBaseSourceRef(configData, configv1alpha1.BaseSourceRef{Digest: "sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b"})).To(Equal([]byte(`baseSource:
+  url: oci://example.com/base
+  ref:
+    digest: sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b
+`)))
+		})
+
+		It("should fail if no base source is configured", func() {
+			_, err := setBaseSourceRef([]byte("variables:\n  domain: example.com\n"), configv1alpha1.BaseSourceRef{Tag: "v1.1.0"})
+			Expect(err).To(MatchError("no base source configured"))
+		})
+
+		It("should fail if the config file is empty", func() {
+			_, err := setBaseSourceRef(nil, configv1alpha1.BaseSourceRef{Tag: "v1.1.0"})
+			Expect(err).To(MatchError("config file is empty"))
+		})
+	})
+
+	Describe("#validate", func() {
+		var opts *Options
+
+		BeforeEach(func() {
+			opts = &Options{
+				LandscapeDir: "/landscape",
+				Config: &configv1alpha1.LandscapeKitConfiguration{
+					BaseSource: &configv1alpha1.BaseSource{URL: "oci://example.com/base", Ref: configv1alpha1.BaseSourceRef{Tag: "v1.0.0"}},
+				},
+				Digest: "sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b",
+			}
+		})
+
+		It("should succeed for a valid ref", func() {
+			Expect(opts.validate()).To(Succeed())
+		})
+
+		It("should fail if several refs are given", func() {
+			opts.Tag = "v1.1.0"
+			Expect(opts.validate()).To(MatchError("exactly one of tag, commit or digest is required"))
+		})
+
+		It("should fail if the ref is not supported by the base source", func() {
+			opts.Digest, opts.Commit = "", "4e2a5d1"
+			Expect(opts.validate()).To(MatchError(ContainSubstring("commit is only supported for Git base sources")))
+		})
+
+		It("should fail if no base source is configured", func() {
+			opts.Config.BaseSource = nil
+			Expect(opts.validate()).To(MatchError(ContainSubstring("no base source is configured")))
+		})
+	})
+})
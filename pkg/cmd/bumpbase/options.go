@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bumpbase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	configv1alpha1validation "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1/validation"
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+)
+
+var configDecoder runtime.Decoder
+
+func init() {
+	configScheme := runtime.NewScheme()
+	utilruntime.Must(configv1alpha1.AddToScheme(configScheme))
+	configDecoder = serializer.NewCodecFactory(configScheme).UniversalDecoder()
+}
+
+// Options contains options for this command.
+type Options struct {
+	*cmd.Options
+
+	configFilePath string
+	configData     []byte
+
+	// LandscapeDir is the directory containing all landscape specific configuration files.
+	LandscapeDir string
+	// Config is the landscape kit configuration.
+	Config *configv1alpha1.LandscapeKitConfiguration
+	// Tag is the Git tag the base source is pinned to.
+	Tag string
+	// Commit is the Git commit the base source is pinned to.
+	Commit string
+	// Digest is the OCI digest the base source is pinned to.
+	Digest string
+}
+
+// Validate validates the options.
+func (o *Options) validate() error {
+	if o.LandscapeDir == "" {
+		return fmt.Errorf("landscape dir is required")
+	}
+
+	if len(slices.DeleteFunc([]string{o.Tag, o.Commit, o.Digest}, func(value string) bool { return value == "" })) != 1 {
+		return fmt.Errorf("exactly one of tag, commit or digest is required")
+	}
+
+	if o.Config.BaseSource == nil {
+		return fmt.Errorf("no base source is configured in %s", o.configFilePath)
+	}
+
+	// Validate the configuration with the new ref, as commits are only supported by Git and digests only by OCI base sources.
+	config := o.Config.DeepCopy()
+	config.BaseSource.Ref = o.ref()
+	if errs := configv1alpha1validation.ValidateLandscapeKitConfiguration(config); len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %v", errs.ToAggregate())
+	}
+
+	return nil
+}
+
+// Complete completes the options.
+func (o *Options) complete() error {
+	if len(o.configFilePath) == 0 {
+		return errors.New("missing config file")
+	}
+
+	var err error
+	o.configData, err = os.ReadFile(o.configFilePath) // #nosec G304 -- Trusted file from CLI argument.
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	o.Config = &configv1alpha1.LandscapeKitConfiguration{}
+	if err = runtime.DecodeInto(configDecoder, o.configData, o.Config); err != nil {
+		return fmt.Errorf("error decoding config: %w", err)
+	}
+
+	return nil
+}
+
+// ref returns the ref the base source is pinned to.
+func (o *Options) ref() configv1alpha1.BaseSourceRef {
+	return configv1alpha1.BaseSourceRef{Tag: o.Tag, Commit: o.Commit, Digest: o.Digest}
+}
+
+func (o *Options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.LandscapeDir, "landscape-dir", "l", "", "Path to a directory containing the landscape specific configuration files, aka overlays.")
+	fs.StringVarP(&o.configFilePath, "config", "c", o.configFilePath, "Path to configuration file.")
+	fs.StringVar(&o.Tag, "tag", "", "Git or OCI tag to pin the base source to.")
+	fs.StringVar(&o.Commit, "commit", "", "Git commit to pin the base source to.")
+	fs.StringVar(&o.Digest, "digest", "", "OCI digest to pin the base source to.")
+}
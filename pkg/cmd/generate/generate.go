@@ -6,13 +6,23 @@ package generate
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
 	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	cloudprofilescomponent "github.com/gardener/gardener-landscape-kit/pkg/components/cloudprofiles"
+	dashboardcomponent "github.com/gardener/gardener-landscape-kit/pkg/components/dashboard"
+	extensionscomponent "github.com/gardener/gardener-landscape-kit/pkg/components/extensions"
 	fluxcomponent "github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	gardencomponent "github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	gardeneroperatorcomponent "github.com/gardener/gardener-landscape-kit/pkg/components/gardeneroperator"
+	helmchartscomponent "github.com/gardener/gardener-landscape-kit/pkg/components/helmcharts"
+	seedscomponent "github.com/gardener/gardener-landscape-kit/pkg/components/seeds"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
 )
 
 // NewCommand creates a new cobra.Command for running gardener-landscape-kit generate.
@@ -29,6 +39,12 @@ gardener-landscape-kit generate --base-dir /path/to/base/dir
 
 # Generate the landscape directory
 gardener-landscape-kit generate --base-dir /path/to/base/dir --landscape-dir /path/to/landscape/dir
+
+# Generate the landscape directory overlaying a group directory shared by multiple landscapes
+gardener-landscape-kit generate --base-dir /path/to/base/dir --group-dir /path/to/group/dir --landscape-dir /path/to/landscape/dir
+
+# Generate the landscape directory and remove its unmodified files that are no longer generated
+gardener-landscape-kit generate --base-dir /path/to/base/dir --landscape-dir /path/to/landscape/dir --prune
 `,
 
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -50,12 +66,53 @@ gardener-landscape-kit generate --base-dir /path/to/base/dir --landscape-dir /pa
 }
 
 func run(_ context.Context, opts *Options) error {
-	componentOpts := components.NewOptions(opts.BaseDir, opts.LandscapeDir, afero.Afero{Fs: afero.NewOsFs()}, opts.Log)
+	fs := afero.Afero{Fs: afero.NewOsFs()}
+	ocmComponents, err := loadOCMComponents(opts, fs)
+	if err != nil {
+		return err
+	}
+
+	componentOpts := components.NewOptions(opts.BaseDir, opts.GroupDirs, opts.LandscapeDir, opts.Config, ocmComponents, fs, opts.Log)
 
-	reg := components.NewRegistry()
+	reg := components.NewRegistry(opts.Workers, opts.Prune)
 
 	// Register all components here
-	reg.RegisterComponent(fluxcomponent.NewComponent())
+	for _, component := range []components.Interface{
+		fluxcomponent.NewComponent(),
+		helmchartscomponent.NewComponent(),
+		gardeneroperatorcomponent.NewComponent(),
+		gardencomponent.NewComponent(),
+		dashboardcomponent.NewComponent(),
+		cloudprofilescomponent.NewComponent(),
+		extensionscomponent.NewComponent(),
+		seedscomponent.NewComponent(),
+	} {
+		if err := reg.RegisterComponent(component); err != nil {
+			return err
+		}
+	}
 
 	return reg.Generate(componentOpts)
 }
+
+// loadOCMComponents loads the OCM components previously resolved by the resolve-ocm-components command, if configured.
+func loadOCMComponents(opts *Options, fs afero.Afero) (*ocmcomponents.Components, error) {
+	if opts.Config.OCM == nil {
+		return nil, nil
+	}
+
+	dir := opts.LandscapeDir
+	if dir == "" {
+		dir = opts.BaseDir
+	}
+	dir = ocm.ResolvedComponentsDir(dir, opts.Config.OCM.RootComponent)
+
+	ocmComponents, err := ocm.LoadResolvedComponents(dir, opts.Config.OCM.RootComponent, fs)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading resolved OCM components from %s: %w", dir, err)
+	}
+	if ocmComponents == nil {
+		opts.Log.Info("OCM components have not been resolved yet, run the resolve-ocm-components command first to use them", "rootComponent", opts.Config.OCM.RootComponent.String(), "dir", dir)
+	}
+	return ocmComponents, nil
+}
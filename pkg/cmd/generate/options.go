@@ -35,10 +35,17 @@ type Options struct {
 
 	// BaseDir is the base directory containing the landscape configuration files.
 	BaseDir string
+	// GroupDirs are the directories containing the configuration files shared by groups of landscapes.
+	// They are ordered from the most general to the most specific group.
+	GroupDirs []string
 	// LandscapeDir is the directory containing all landscape specific configuration files.
 	LandscapeDir string
 	// Config is the path to the landscape kit configuration file.
 	Config *configv1alpha1.LandscapeKitConfiguration
+	// Workers is the maximum number of components generated concurrently.
+	Workers int
+	// Prune enables the removal of previously generated files that are no longer generated and have not been modified.
+	Prune bool
 }
 
 // Validate validates the options.
@@ -47,6 +54,14 @@ func (o *Options) validate() error {
 		return fmt.Errorf("base dir is required")
 	}
 
+	if len(o.GroupDirs) > 0 && o.LandscapeDir == "" {
+		return fmt.Errorf("group dirs require a landscape dir")
+	}
+
+	if o.Workers < 1 {
+		return fmt.Errorf("workers must be at least 1")
+	}
+
 	if errs := configv1alpha1validation.ValidateLandscapeKitConfiguration(o.Config); len(errs) > 0 {
 		return fmt.Errorf("invalid configuration: %v", errs.ToAggregate())
 	}
@@ -75,6 +90,9 @@ func (o *Options) complete() error {
 
 func (o *Options) addFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&o.BaseDir, "base-dir", "b", "", "Path to a directory containing the landscape base configuration files.")
+	fs.StringArrayVar(&o.GroupDirs, "group-dir", nil, "Path to a directory containing configuration files shared by a group of landscapes. Can be repeated to form a chain of overlays from the base to the landscape, ordered from the most general to the most specific group.")
 	fs.StringVarP(&o.LandscapeDir, "landscape-dir", "l", "", "Path to a directory containing the landscape specific configuration files, aka overlays.")
 	fs.StringVarP(&o.configFilePath, "config", "c", o.configFilePath, "Path to configuration file.")
+	fs.IntVar(&o.Workers, "workers", 5, "Maximum number of components that are generated concurrently.")
+	fs.BoolVar(&o.Prune, "prune", false, "Remove previously generated files of the landscape directory that are no longer generated, unless they have been modified. Files of the shared base and group directories are only reported.")
 }
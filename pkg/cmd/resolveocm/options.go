@@ -17,6 +17,7 @@ import (
 	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
 	configv1alpha1validation "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1/validation"
 	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm"
 )
 
 var configDecoder runtime.Decoder
@@ -73,7 +74,7 @@ func (o *Options) addFlags(fs *pflag.FlagSet) {
 }
 
 func (o *Options) effectiveOutputDir(subdir string) string {
-	outputDir := path.Join(o.LandscapeDir, "ocm", o.Config.RootComponent.Name, o.Config.RootComponent.Version)
+	outputDir := ocm.ResolvedComponentsDir(o.LandscapeDir, o.Config.RootComponent)
 	if subdir != "" {
 		outputDir = path.Join(outputDir, subdir)
 	}
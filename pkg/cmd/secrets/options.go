@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+)
+
+// Options contains options for this command.
+type Options struct {
+	*cmd.Options
+
+	// LandscapeDir is the directory containing all landscape specific configuration files, including the SOPS configuration.
+	LandscapeDir string
+	// SOPSBinary is the path of the sops binary the secrets are encrypted and edited with.
+	SOPSBinary string
+}
+
+// validate validates the options.
+func (o *Options) validate() error {
+	if o.LandscapeDir == "" {
+		return fmt.Errorf("landscape dir is required")
+	}
+
+	if o.SOPSBinary == "" {
+		return fmt.Errorf("sops binary is required")
+	}
+
+	return nil
+}
+
+func (o *Options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.LandscapeDir, "landscape-dir", "l", "", "Path to a directory containing the landscape specific configuration files, aka overlays.")
+	fs.StringVar(&o.SOPSBinary, "sops", "sops", "Path of the sops binary the secrets are encrypted and edited with.")
+}
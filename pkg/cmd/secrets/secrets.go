@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+)
+
+// execCommand is an alias for exec.CommandContext for testing purposes, so that the sops binary does not need to be installed.
+var execCommand = exec.CommandContext
+
+// NewCommand creates a new cobra.Command for running gardener-landscape-kit secrets.
+func NewCommand(globalOpts *cmd.Options) *cobra.Command {
+	opts := &Options{Options: globalOpts}
+
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Encrypts and edits the secrets scaffolded for the components",
+		Long: "Encrypts and edits the secrets scaffolded for the components with the sops binary, which has to be installed in version 3.9 or later. " +
+			"The secrets are encrypted for the age recipients of the SOPS configuration generated into the landscape directory.",
+	}
+
+	for _, subcommand := range []*cobra.Command{
+		newEncryptCommand(opts),
+		newEditCommand(opts),
+	} {
+		cmd.AddCommand(subcommand)
+	}
+
+	opts.addFlags(cmd.PersistentFlags())
+
+	return cmd
+}
+
+func newEncryptCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypts all secrets in the landscape directory that are not encrypted yet",
+		Example: `# Encrypt the filled in secret stubs
+gardener-landscape-kit secrets encrypt --landscape-dir /path/to/landscape/dir
+`,
+		Args: cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			return runEncrypt(cmd.Context(), opts)
+		},
+	}
+}
+
+func newEditCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <file>",
+		Short: "Edits a secret in the editor configured for sops, it is encrypted first if needed",
+		Example: `# Edit a secret of the garden component
+gardener-landscape-kit secrets edit --landscape-dir /path/to/landscape/dir /path/to/landscape/dir/components/virtual-garden/garden/resources/secret-dns.sops.yaml
+`,
+		Args: cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			return runEdit(cmd.Context(), opts, args[0])
+		},
+	}
+}
+
+func runEncrypt(ctx context.Context, opts *Options) error {
+	filePaths, err := findUnencryptedSecrets(opts.LandscapeDir)
+	if err != nil {
+		return err
+	}
+	if len(filePaths) == 0 {
+		opts.Log.Info("All secrets are encrypted already", "landscapeDir", opts.LandscapeDir)
+		return nil
+	}
+
+	for _, filePath := range filePaths {
+		if err := runSOPS(ctx, opts, "--encrypt", "--in-place", filePath); err != nil {
+			return fmt.Errorf("failed encrypting secret %s: %w", filePath, err)
+		}
+		opts.Log.Info("Encrypted secret", "file", filePath)
+	}
+	return nil
+}
+
+func runEdit(ctx context.Context, opts *Options, filePath string) error {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+	if !components.IsSecretFile(filePath) {
+		return fmt.Errorf("%s is not a secret scaffolded for the components", filePath)
+	}
+
+	encrypted, err := components.IsSecretEncrypted(filePath, afero.Afero{Fs: afero.NewOsFs()})
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		// sops only edits encrypted files, so the stub is encrypted first.
+		if err := runSOPS(ctx, opts, "--encrypt", "--in-place", filePath); err != nil {
+			return fmt.Errorf("failed encrypting secret %s: %w", filePath, err)
+		}
+	}
+
+	if err := runSOPS(ctx, opts, "edit", filePath); err != nil {
+		return fmt.Errorf("failed editing secret %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// findUnencryptedSecrets returns the absolute paths of the secrets in the given landscape directory that are not encrypted yet.
+// Hidden directories, e.g. the Git directory and the generated defaults, are skipped.
+func findUnencryptedSecrets(landscapeDir string) ([]string, error) {
+	landscapeDir, err := filepath.Abs(landscapeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		filePaths []string
+		osFs      = afero.Afero{Fs: afero.NewOsFs()}
+	)
+	if err := filepath.WalkDir(landscapeDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != landscapeDir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !components.IsSecretFile(filePath) {
+			return nil
+		}
+
+		encrypted, err := components.IsSecretEncrypted(filePath, osFs)
+		if err != nil {
+			return err
+		}
+		if !encrypted {
+			filePaths = append(filePaths, filePath)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return filePaths, nil
+}
+
+// runSOPS runs the sops binary with the given arguments in the landscape directory, so that sops finds the generated SOPS configuration.
+func runSOPS(ctx context.Context, opts *Options, args ...string) error {
+	command := execCommand(ctx, opts.SOPSBinary, args...)
+	command.Dir = opts.LandscapeDir
+	command.Stdin, command.Stdout, command.Stderr = opts.In, opts.Out, opts.ErrOut
+	return command.Run()
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/cmd"
+)
+
+var _ = Describe("Secrets", func() {
+	const (
+		stub      = "apiVersion: v1\nkind: Secret\nmetadata:\n  name: dns\n  namespace: garden\nstringData: {}\ntype: Opaque\n"
+		encrypted = "apiVersion: v1\nkind: Secret\nmetadata:\n  name: backup\n  namespace: garden\nstringData:\n  token: ENC[AES256_GCM,data:abc,type:str]\nsops:\n  age: []\n"
+	)
+
+	var (
+		landscapeDir string
+		opts         *Options
+		calls        [][]string
+	)
+
+	writeFile := func(relativePath, content string) string {
+		filePath := filepath.Join(landscapeDir, relativePath)
+		Expect(os.MkdirAll(filepath.Dir(filePath), 0700)).To(Succeed())
+		Expect(os.WriteFile(filePath, []byte(content), 0600)).To(Succeed())
+		return filePath
+	}
+
+	BeforeEach(func() {
+		landscapeDir = GinkgoT().TempDir()
+		opts = &Options{
+			Options:      &cmd.Options{IOStreams: genericiooptions.NewTestIOStreamsDiscard(), Log: logr.Discard()},
+			LandscapeDir: landscapeDir,
+			SOPSBinary:   "sops",
+		}
+
+		calls = nil
+		DeferCleanup(func(original func(context.Context, string, ...string) *exec.Cmd) {
+			execCommand = original
+		}, execCommand)
+		execCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+			calls = append(calls, append([]string{name}, args...))
+			return exec.CommandContext(ctx, "true")
+		}
+	})
+
+	Describe("#runEncrypt", func() {
+		It("should encrypt the secrets that are not encrypted yet", func() {
+			dnsPath := writeFile("components/virtual-garden/garden/resources/secret-dns.sops.yaml", stub)
+			writeFile("components/virtual-garden/garden/resources/secret-backup.sops.yaml", encrypted)
+			writeFile("components/virtual-garden/garden/resources/garden.yaml", stub)
+			writeFile(".glk/defaults/components/virtual-garden/garden/resources/secret-dns.sops.yaml", stub)
+
+			Expect(runEncrypt(context.Background(), opts)).To(Succeed())
+
+			Expect(calls).To(Equal([][]string{{"sops", "--encrypt", "--in-place", dnsPath}}))
+		})
+
+		It("should do nothing if all secrets are encrypted", func() {
+			writeFile("components/virtual-garden/garden/resources/secret-backup.sops.yaml", encrypted)
+
+			Expect(runEncrypt(context.Background(), opts)).To(Succeed())
+
+			Expect(calls).To(BeEmpty())
+		})
+
+		It("should fail if sops fails", func() {
+			writeFile("components/virtual-garden/garden/resources/secret-dns.sops.yaml", stub)
+			execCommand = func(ctx context.Context, _ string, _ ...string) *exec.Cmd {
+				return exec.CommandContext(ctx, "false")
+			}
+
+			Expect(runEncrypt(context.Background(), opts)).To(MatchError(ContainSubstring("failed encrypting secret")))
+		})
+	})
+
+	Describe("#runEdit", func() {
+		It("should encrypt the secret before editing it", func() {
+			dnsPath := writeFile("components/virtual-garden/garden/resources/secret-dns.sops.yaml", stub)
+
+			Expect(runEdit(context.Background(), opts, dnsPath)).To(Succeed())
+
+			Expect(calls).To(Equal([][]string{
+				{"sops", "--encrypt", "--in-place", dnsPath},
+				{"sops", "edit", dnsPath},
+			}))
+		})
+
+		It("should edit encrypted secrets directly", func() {
+			backupPath := writeFile("components/virtual-garden/garden/resources/secret-backup.sops.yaml", encrypted)
+
+			Expect(runEdit(context.Background(), opts, backupPath)).To(Succeed())
+
+			Expect(calls).To(Equal([][]string{{"sops", "edit", backupPath}}))
+		})
+
+		It("should reject files that are no scaffolded secrets", func() {
+			gardenPath := writeFile("components/virtual-garden/garden/resources/garden.yaml", stub)
+
+			Expect(runEdit(context.Background(), opts, gardenPath)).To(MatchError(ContainSubstring("is not a secret scaffolded for the components")))
+			Expect(calls).To(BeEmpty())
+		})
+	})
+})
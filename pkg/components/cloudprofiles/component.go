@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudprofiles
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+// ComponentName is the name of the CloudProfiles component.
+const ComponentName = "cloud-profiles"
+
+// Config is the configuration of the CloudProfiles component.
+type Config struct {
+	// CloudProfiles are the CloudProfiles of the landscape.
+	CloudProfiles []CloudProfile `json:"cloudProfiles"`
+}
+
+// CloudProfile is a CloudProfile of the landscape, whose Kubernetes versions are taken from the resolved OCM components.
+type CloudProfile struct {
+	// Name is the name of the CloudProfile.
+	Name string `json:"name"`
+	// Type is the provider type of the CloudProfile.
+	Type string `json:"type"`
+	// KubernetesVersions contain the classification and expiration date of Kubernetes versions. Versions without entry are
+	// offered without classification and expiration date.
+	KubernetesVersions []gardencorev1beta1.ExpirableVersion `json:"kubernetesVersions,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new CloudProfiles component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+// The CloudProfiles are applied to the virtual garden, hence they are rolled out after the Garden resource.
+func (c *component) Dependencies() []string {
+	return []string{garden.ComponentName}
+}
+
+// Layer returns the rollout layer of the component.
+func (c *component) Layer() components.Layer {
+	return components.LayerVirtualGarden
+}
+
+// FluxKustomizations returns the reference to the Flux Kustomization of the CloudProfiles, which is placed into the garden namespace.
+func (c *component) FluxKustomizations(options components.Options) []kustomizev1.DependencyReference {
+	config, err := decodeConfig(options)
+	if err != nil || len(config.CloudProfiles) == 0 || options.GetOCMComponents() == nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+	return []kustomizev1.DependencyReference{fluxKustomizationOptions(options).DependencyReference()}
+}
+
+// GenerateBase does nothing, the CloudProfiles only consist of landscape specific settings.
+func (c *component) GenerateBase(_ components.Options) error {
+	return nil
+}
+
+// GenerateLandscape generates a manifest for each configured CloudProfile into a Flux managed directory of the landscape, which is
+// applied to the virtual garden.
+// The manifests only contain the type and the Kubernetes versions of the CloudProfiles. All other settings, e.g. the machine
+// images and regions, are maintained in the manifests by the user and kept by the three-way merge, while the Kubernetes
+// versions follow the root component version.
+func (c *component) GenerateLandscape(options components.Options) error {
+	config, err := decodeConfig(options)
+	if err != nil || len(config.CloudProfiles) == 0 {
+		return err
+	}
+	ocmComponents := options.GetOCMComponents()
+	if ocmComponents == nil {
+		options.GetLogger().Info("Skipping component as the OCM components have not been resolved", "component", ComponentName)
+		return nil
+	}
+
+	versions, err := ocmComponents.GetKubernetesVersions()
+	if err != nil {
+		return fmt.Errorf("failed to get the Kubernetes versions of component %q: %w", ComponentName, err)
+	}
+
+	objects := make(map[string][]byte, len(config.CloudProfiles))
+	for _, cloudProfile := range config.CloudProfiles {
+		content, err := newCloudProfile(cloudProfile, versions)
+		if err != nil {
+			return err
+		}
+		objects[FileName(cloudProfile.Name)] = content
+	}
+
+	return components.WriteFluxComponent(options, components.ComponentDir(c.Layer(), ComponentName), fluxKustomizationOptions(options), "", objects, nil)
+}
+
+// fluxKustomizationOptions returns the options of the Flux Kustomization applying the CloudProfiles to the virtual garden.
+func fluxKustomizationOptions(options components.Options) kustomization.FluxKustomizationOptions {
+	return garden.TargetVirtualGarden(kustomization.FluxKustomizationOptions{
+		Name:      ComponentName,
+		SourceRef: flux.LandscapeSourceRef(options),
+	})
+}
+
+// FileName returns the name of the manifest file of the CloudProfile with the given name.
+func FileName(name string) string {
+	return "cloudprofile-" + name + ".yaml"
+}
+
+// newCloudProfile returns the manifest of the given CloudProfile offering the given Kubernetes versions in descending order.
+// It is built as map, so that it only contains the generated settings.
+func newCloudProfile(cloudProfile CloudProfile, versions []string) ([]byte, error) {
+	for _, configured := range cloudProfile.KubernetesVersions {
+		if !slices.Contains(versions, configured.Version) {
+			return nil, fmt.Errorf("kubernetes version %s of CloudProfile %q is not contained in the resolved OCM components, available versions: %s",
+				configured.Version, cloudProfile.Name, strings.Join(versions, ", "))
+		}
+	}
+
+	expirableVersions := make([]gardencorev1beta1.ExpirableVersion, 0, len(versions))
+	for _, version := range slices.Backward(versions) {
+		expirableVersion := gardencorev1beta1.ExpirableVersion{Version: version}
+		if i := slices.IndexFunc(cloudProfile.KubernetesVersions, func(v gardencorev1beta1.ExpirableVersion) bool { return v.Version == version }); i >= 0 {
+			expirableVersion = cloudProfile.KubernetesVersions[i]
+		}
+		expirableVersions = append(expirableVersions, expirableVersion)
+	}
+
+	return yaml.Marshal(map[string]any{
+		"apiVersion": gardencorev1beta1.SchemeGroupVersion.String(),
+		"kind":       "CloudProfile",
+		"metadata": map[string]any{
+			"name": cloudProfile.Name,
+		},
+		"spec": map[string]any{
+			"type": cloudProfile.Type,
+			"kubernetes": gardencorev1beta1.KubernetesSettings{
+				Versions: expirableVersions,
+			},
+		},
+	})
+}
+
+// decodeConfig decodes the configuration of the component and checks that the CloudProfiles are named uniquely and have a type.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+
+	names := sets.New[string]()
+	for i, cloudProfile := range config.CloudProfiles {
+		if errs := utilvalidation.IsDNS1123Subdomain(cloudProfile.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("CloudProfile %d of component %q has an invalid name %q: %s", i, ComponentName, cloudProfile.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(cloudProfile.Name) {
+			return nil, fmt.Errorf("CloudProfile %q of component %q is configured more than once", cloudProfile.Name, ComponentName)
+		}
+		names.Insert(cloudProfile.Name)
+
+		if cloudProfile.Type == "" {
+			return nil, fmt.Errorf("CloudProfile %q of component %q is missing type", cloudProfile.Name, ComponentName)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudprofiles_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components/cloudprofiles"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+var _ = Describe("CloudProfiles Component", func() {
+	var (
+		fs afero.Afero
+
+		cloudProfilePath = "/repo/landscape/components/virtual-garden/cloud-profiles/resources/cloudprofile-aws.yaml"
+	)
+
+	// resolveComponents resolves a root component labelled as Kubernetes component, which contains the kube-apiserver images of the
+	// given versions.
+	resolveComponents := func(versions ...string) *ocmcomponents.Components {
+		var resources []string
+		for _, version := range versions {
+			resources = append(resources, fmt.Sprintf(`{"name": "kube-apiserver", "version": %[1]q, "type": "ociImage", "relation": "external",
+  "labels": [{"name": "imagevector.gardener.cloud/name", "value": "registry.k8s.io/kube-apiserver"}],
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/kube-apiserver:v%[1]s"}}`, version))
+		}
+
+		return test.OCMFixture{
+			Descriptors: map[ocmcomponents.ComponentReference]string{
+				ocmcomponents.ComponentReferenceFromNameAndVersion(test.RootComponent.Name, test.RootComponent.Version): fmt.Sprintf(`{"meta": {"schemaVersion": "v2"}, "component": {
+  "name": %q, "version": %q, "provider": "test", "repositoryContexts": [], "sources": [], "componentReferences": [],
+  "labels": [{"name": "imagevector.gardener.cloud/application", "value": "kubernetes"}],
+  "resources": [%s]}}`, test.RootComponent.Name, test.RootComponent.Version, strings.Join(resources, ",")),
+			},
+		}.Resolve()
+	}
+
+	// generate generates the cloud-profiles component with the given OCM components and configuration.
+	generate := func(ocmComponents *ocmcomponents.Components, config string) error {
+		return test.Generate(test.NewOptions(fs, ocmComponents, cloudprofiles.ComponentName, config), garden.NewComponent(), cloudprofiles.NewComponent())
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+	})
+
+	It("should generate the Kubernetes versions of the OCM components with the configured classifications", func() {
+		Expect(generate(resolveComponents("1.32.8", "1.33.4", "1.31.10"), `{"cloudProfiles": [{
+  "name": "aws",
+  "type": "aws",
+  "kubernetesVersions": [{"version": "1.31.10", "classification": "deprecated", "expirationDate": "2026-12-31T23:59:59Z"}]
+}]}`)).To(Succeed())
+
+		Expect(fs.ReadFile(cloudProfilePath)).To(MatchYAML(`apiVersion: core.gardener.cloud/v1beta1
+kind: CloudProfile
+metadata:
+  name: aws
+spec:
+  kubernetes:
+    versions:
+    - version: 1.33.4
+    - version: 1.32.8
+    - classification: deprecated
+      expirationDate: "2026-12-31T23:59:59Z"
+      version: 1.31.10
+  type: aws
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/cloud-profiles/flux-kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: cloud-profiles
+  namespace: garden
+spec:
+  dependsOn:
+  - name: garden
+    namespace: flux-system
+  interval: 10m0s
+  kubeConfig:
+    secretRef:
+      key: kubeconfig
+      name: gardener-internal
+  path: landscape/components/virtual-garden/cloud-profiles/resources
+  postBuild:
+    substituteFrom:
+    - kind: ConfigMap
+      name: glk-variables
+      optional: true
+  prune: false
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+    namespace: flux-system
+status: {}
+`))
+	})
+
+	It("should update the Kubernetes versions and keep the settings maintained by the user", func() {
+		config := `{"cloudProfiles": [{"name": "aws", "type": "aws"}]}`
+		Expect(generate(resolveComponents("1.32.8", "1.33.3"), config)).To(Succeed())
+
+		content, err := fs.ReadFile(cloudProfilePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.WriteFile(cloudProfilePath, append(content, []byte(`  regions:
+  - name: eu-west-1
+`)...), 0600)).To(Succeed())
+
+		Expect(generate(resolveComponents("1.32.8", "1.33.4"), config)).To(Succeed())
+
+		Expect(fs.ReadFile(cloudProfilePath)).To(MatchYAML(`apiVersion: core.gardener.cloud/v1beta1
+kind: CloudProfile
+metadata:
+  name: aws
+spec:
+  kubernetes:
+    versions:
+    - version: 1.33.4
+    - version: 1.32.8
+  regions:
+  - name: eu-west-1
+  type: aws
+`))
+	})
+
+	It("should skip the component if the OCM components have not been resolved", func() {
+		Expect(generate(nil, `{"cloudProfiles": [{"name": "aws", "type": "aws"}]}`)).To(Succeed())
+
+		Expect(fs.DirExists("/repo/landscape/components/virtual-garden/cloud-profiles")).To(BeFalse())
+	})
+
+	It("should fail if a configured Kubernetes version is not contained in the OCM components", func() {
+		Expect(generate(resolveComponents("1.33.4"), `{"cloudProfiles": [{"name": "aws", "type": "aws", "kubernetesVersions": [{"version": "1.30.0"}]}]}`)).To(
+			MatchError(ContainSubstring(`kubernetes version 1.30.0 of CloudProfile "aws" is not contained in the resolved OCM components, available versions: 1.33.4`)))
+	})
+
+	It("should fail if the type of a CloudProfile is missing", func() {
+		Expect(generate(resolveComponents("1.33.4"), `{"cloudProfiles": [{"name": "aws"}]}`)).To(
+			MatchError(ContainSubstring(`CloudProfile "aws" of component "cloud-profiles" is missing type`)))
+	})
+})
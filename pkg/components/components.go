@@ -5,8 +5,16 @@
 package components
 
 import (
+	"fmt"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	"github.com/go-logr/logr"
 	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
 )
 
 const (
@@ -18,16 +26,42 @@ const (
 type Options interface {
 	// GetBaseDir returns the base directory that serves as the foundation (base) for any landscape.
 	GetBaseDir() string
+	// GetGroupDirs returns the directories of the groups the landscape belongs to, ordered from the most general to the most specific one.
+	// Each group overlays the base or the previous group and is overlaid by the next group or the landscape.
+	GetGroupDirs() []string
 	// GetLandscapeDir returns the landscape directory. If the returned path is empty, only the base directory should be generated.
 	GetLandscapeDir() string
 	// GetFilesystem returns the filesystem to use.
 	GetFilesystem() afero.Afero
 	// GetLogger returns the logger instance.
 	GetLogger() logr.Logger
+	// GetFluxDependsOn returns the references to the Flux Kustomizations of the components the generated component depends on.
+	GetFluxDependsOn() []kustomizev1.DependencyReference
+	// GetConfig returns the landscape kit configuration. It is never nil.
+	GetConfig() *configv1alpha1.LandscapeKitConfiguration
+	// GetComponentConfig returns the configuration section of the generated component, or nil if there is none.
+	GetComponentConfig() *configv1alpha1.ComponentConfig
+	// GetOCMComponents returns the resolved OCM components of the configured root component.
+	// It returns nil if no OCM configuration is given or the components have not been resolved yet.
+	GetOCMComponents() *ocmcomponents.Components
+	// GetNamespaces returns the namespaces declared by all components and the configuration, sorted by name.
+	// It returns nil if the namespaces have not been collected yet.
+	GetNamespaces() []Namespace
+	// GetSecrets returns the secrets declared by the generated component, see SecretDeclarer.
+	// It returns nil if secrets are not configured.
+	GetSecrets() []Secret
 }
 
 // Interface is the components interface that each component must implement.
 type Interface interface {
+	// Name returns the unique name of the component.
+	// It is also used as the name of the Flux Kustomization managing the component, if there is any, see FluxKustomizationDeclarer.
+	Name() string
+	// Dependencies returns the names of the components this component depends on.
+	Dependencies() []string
+	// Layer returns the rollout layer the component is assigned to.
+	// The component is expected to write its Flux managed directory to ComponentDir of its layer.
+	Layer() Layer
 	// GenerateBase generates the component base dir.
 	GenerateBase(Options) error
 	// GenerateLandscape generates the component landscape dir.
@@ -35,10 +69,13 @@ type Interface interface {
 }
 
 type options struct {
-	baseDir      string
-	landscapeDir string
-	filesystem   afero.Afero
-	logger       logr.Logger
+	baseDir       string
+	groupDirs     []string
+	landscapeDir  string
+	config        *configv1alpha1.LandscapeKitConfiguration
+	ocmComponents *ocmcomponents.Components
+	filesystem    afero.Afero
+	logger        logr.Logger
 }
 
 // GetBaseDir returns the base directory that serves as the foundation (base) for any landscape.
@@ -46,6 +83,11 @@ func (o options) GetBaseDir() string {
 	return o.baseDir
 }
 
+// GetGroupDirs returns the directories of the groups the landscape belongs to, ordered from the most general to the most specific one.
+func (o options) GetGroupDirs() []string {
+	return o.groupDirs
+}
+
 // GetLandscapeDir returns the landscape directory. If the returned path is empty, only the base directory should be generated.
 func (o options) GetLandscapeDir() string {
 	return o.landscapeDir
@@ -61,12 +103,136 @@ func (o options) GetLogger() logr.Logger {
 	return o.logger
 }
 
+// GetFluxDependsOn returns the references to the Flux Kustomizations of the components the generated component depends on.
+func (o options) GetFluxDependsOn() []kustomizev1.DependencyReference {
+	return nil
+}
+
+// GetConfig returns the landscape kit configuration. It is never nil.
+func (o options) GetConfig() *configv1alpha1.LandscapeKitConfiguration {
+	return o.config
+}
+
+// GetComponentConfig returns the configuration section of the generated component, or nil if there is none.
+func (o options) GetComponentConfig() *configv1alpha1.ComponentConfig {
+	return nil
+}
+
+// GetOCMComponents returns the resolved OCM components of the configured root component.
+// It returns nil if no OCM configuration is given or the components have not been resolved yet.
+func (o options) GetOCMComponents() *ocmcomponents.Components {
+	return o.ocmComponents
+}
+
+// GetNamespaces returns the namespaces declared by all components and the configuration, sorted by name.
+// It returns nil if the namespaces have not been collected yet.
+func (o options) GetNamespaces() []Namespace {
+	return nil
+}
+
+// GetSecrets returns the secrets declared by the generated component.
+func (o options) GetSecrets() []Secret {
+	return nil
+}
+
 // NewOptions returns a new Options instance.
-func NewOptions(baseDir string, landscapeDir string, fs afero.Afero, logger logr.Logger) Options {
+// If config is nil, an empty configuration is used. ocmComponents may be nil if the OCM components have not been resolved.
+func NewOptions(baseDir string, groupDirs []string, landscapeDir string, config *configv1alpha1.LandscapeKitConfiguration, ocmComponents *ocmcomponents.Components, fs afero.Afero, logger logr.Logger) Options {
+	if config == nil {
+		config = &configv1alpha1.LandscapeKitConfiguration{}
+	}
+
 	return &options{
-		baseDir:      baseDir,
-		landscapeDir: landscapeDir,
-		filesystem:   fs,
-		logger:       logger,
+		baseDir:       baseDir,
+		groupDirs:     groupDirs,
+		landscapeDir:  landscapeDir,
+		config:        config,
+		ocmComponents: ocmComponents,
+		filesystem:    fs,
+		logger:        logger,
+	}
+}
+
+type componentOptions struct {
+	Options
+
+	dependsOn       []kustomizev1.DependencyReference
+	componentConfig *configv1alpha1.ComponentConfig
+	secrets         []Secret
+}
+
+// GetFluxDependsOn returns the references to the Flux Kustomizations of the components the generated component depends on.
+func (o *componentOptions) GetFluxDependsOn() []kustomizev1.DependencyReference {
+	return o.dependsOn
+}
+
+// GetComponentConfig returns the configuration section of the generated component, or nil if there is none.
+func (o *componentOptions) GetComponentConfig() *configv1alpha1.ComponentConfig {
+	return o.componentConfig
+}
+
+// GetSecrets returns the secrets declared by the generated component, see SecretDeclarer.
+func (o *componentOptions) GetSecrets() []Secret {
+	return o.secrets
+}
+
+// newComponentOptions returns the options passed to the given component.
+// The Flux dependencies are derived from the declared dependencies of the component, the secrets are declared by the component itself.
+// The dependencies reference the namespaces of the Flux Kustomizations explicitly, as the dependent Flux Kustomization might be placed
+// into another namespace.
+func newComponentOptions(opts Options, component Interface) Options {
+	var dependsOn []kustomizev1.DependencyReference
+	for _, dependency := range component.Dependencies() {
+		dependsOn = append(dependsOn, fluxKustomizations(opts, dependency)...)
+	}
+
+	var componentConfig *configv1alpha1.ComponentConfig
+	for i, conf := range opts.GetConfig().Components {
+		if conf.Name == component.Name() {
+			componentConfig = &opts.GetConfig().Components[i]
+			break
+		}
+	}
+
+	componentOpts := &componentOptions{
+		Options:         opts,
+		dependsOn:       dependsOn,
+		componentConfig: componentConfig,
+	}
+	componentOpts.secrets = declaredSecrets(componentOpts, component)
+	return componentOpts
+}
+
+// FluxKustomizationDeclarer is implemented by components that do not generate a single Flux Kustomization named like the component in
+// the flux-system namespace, e.g. one Flux Kustomization per configured item or Flux Kustomizations applying to the virtual garden.
+// The Flux Kustomizations of dependent components depend on all declared ones.
+type FluxKustomizationDeclarer interface {
+	// FluxKustomizations returns the references to the Flux Kustomizations generated by the component.
+	FluxKustomizations(Options) []kustomizev1.DependencyReference
+}
+
+// fluxKustomizations returns the references to the Flux Kustomizations generated by the component with the given name. They are only
+// known during the generation of the registry, otherwise a Flux Kustomization named like the component in the flux-system namespace
+// is assumed.
+func fluxKustomizations(opts Options, componentName string) []kustomizev1.DependencyReference {
+	if generateOpts, ok := opts.(*generateOptions); ok {
+		if references, ok := generateOpts.fluxKustomizations[componentName]; ok {
+			return references
+		}
+	}
+	return []kustomizev1.DependencyReference{{Name: componentName, Namespace: kustomization.FluxSystemNamespaceName}}
+}
+
+// DecodeComponentConfig decodes the component specific configuration of the generated component into the given object.
+// The object is left untouched if there is no configuration for the component. Unknown fields are rejected.
+func DecodeComponentConfig(opts Options, into any) error {
+	componentConfig := opts.GetComponentConfig()
+	if componentConfig == nil || componentConfig.Config == nil || len(componentConfig.Config.Raw) == 0 {
+		return nil
+	}
+
+	if err := yaml.UnmarshalStrict(componentConfig.Config.Raw, into); err != nil {
+		return fmt.Errorf("failed to decode configuration of component %q: %w", componentConfig.Name, err)
 	}
+	return nil
 }
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+)
+
+var _ = Describe("Options", func() {
+	type componentConfig struct {
+		Replicas int `json:"replicas"`
+	}
+
+	var (
+		config *configv1alpha1.LandscapeKitConfiguration
+		opts   Options
+	)
+
+	BeforeEach(func() {
+		config = &configv1alpha1.LandscapeKitConfiguration{
+			Components: []configv1alpha1.ComponentConfig{
+				{Name: "other", Config: &runtime.RawExtension{Raw: []byte(`{"replicas":1}`)}},
+				{Name: "test", Config: &runtime.RawExtension{Raw: []byte(`{"replicas":3}`)}},
+			},
+		}
+		opts = NewOptions("/baseDir", nil, "/landscapeDir", config, nil, afero.Afero{Fs: afero.NewMemMapFs()}, logr.Discard())
+	})
+
+	It("should default to an empty configuration", func() {
+		opts = NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, afero.Afero{Fs: afero.NewMemMapFs()}, logr.Discard())
+
+		Expect(opts.GetConfig()).To(Equal(&configv1alpha1.LandscapeKitConfiguration{}))
+		Expect(opts.GetComponentConfig()).To(BeNil())
+		Expect(opts.GetOCMComponents()).To(BeNil())
+	})
+
+	Describe("#DecodeComponentConfig", func() {
+		It("should decode the configuration of the generated component", func() {
+			componentOpts := newComponentOptions(opts, &fakeComponent{name: "test"})
+			Expect(componentOpts.GetConfig()).To(BeIdenticalTo(config))
+			Expect(componentOpts.GetComponentConfig()).To(BeIdenticalTo(&config.Components[1]))
+
+			var conf componentConfig
+			Expect(DecodeComponentConfig(componentOpts, &conf)).To(Succeed())
+			Expect(conf.Replicas).To(Equal(3))
+		})
+
+		It("should leave the object untouched if there is no configuration for the component", func() {
+			conf := componentConfig{Replicas: 2}
+			Expect(DecodeComponentConfig(newComponentOptions(opts, &fakeComponent{name: "unconfigured"}), &conf)).To(Succeed())
+			Expect(conf.Replicas).To(Equal(2))
+		})
+
+		It("should reject unknown fields", func() {
+			config.Components[1].Config.Raw = []byte(`{"replica":3}`)
+
+			var conf componentConfig
+			Expect(DecodeComponentConfig(newComponentOptions(opts, &fakeComponent{name: "test"}), &conf)).To(MatchError(ContainSubstring(`failed to decode configuration of component "test"`)))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/helm"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// ComponentName is the name of the Gardener dashboard component.
+	ComponentName = "gardener-dashboard"
+	// ChartResourceName is the name of the OCM resource containing the dashboard chart.
+	ChartResourceName = "gardener-dashboard"
+	// ImageResourceName is the name of the OCM resource containing the dashboard image.
+	ImageResourceName = "gardener-dashboard"
+
+	// ConfigPatchFileName is the name of the patch file containing the values derived from the component configuration.
+	ConfigPatchFileName = "config-patch.yaml"
+
+	// defaultOIDCClientID is the client ID of the dashboard at the OIDC provider if none is configured.
+	defaultOIDCClientID = "dashboard"
+)
+
+// Config is the configuration of the Gardener dashboard component.
+type Config struct {
+	// Ingress contains the settings of the ingress of the dashboard.
+	Ingress Ingress `json:"ingress"`
+	// OIDC contains the settings of the OIDC provider the users log in with.
+	OIDC OIDC `json:"oidc"`
+	// Values are further values of the dashboard chart in the landscape, e.g. the OIDC client secret.
+	// They take precedence over the values derived from the other settings.
+	Values map[string]any `json:"values,omitempty"`
+}
+
+// Ingress contains the settings of the ingress of the dashboard.
+type Ingress struct {
+	// Hosts are the host names the dashboard is served at.
+	Hosts []string `json:"hosts"`
+	// TLSSecretName is the name of the secret in the garden namespace containing the TLS certificate of the hosts.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
+
+// OIDC contains the settings of the OIDC provider.
+type OIDC struct {
+	// IssuerURL is the URL of the OIDC provider.
+	IssuerURL string `json:"issuerURL"`
+	// ClientID is the client ID of the dashboard at the OIDC provider. Defaults to dashboard.
+	ClientID string `json:"clientID,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new Gardener dashboard component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+// The dashboard is deployed after the Garden resource, as it requires the virtual garden to be available.
+func (c *component) Dependencies() []string {
+	return []string{garden.ComponentName}
+}
+
+// Layer returns the rollout layer of the component.
+func (c *component) Layer() components.Layer {
+	return components.LayerVirtualGarden
+}
+
+// Namespaces returns the namespaces required by the component.
+func (c *component) Namespaces(_ components.Options) []components.Namespace {
+	return []components.Namespace{{Name: flux.GardenNamespaceName, PodSecurityLevel: components.PodSecurityLevelPrivileged}}
+}
+
+// GenerateBase generates the chart source and the HelmRelease of the dashboard into the base directory.
+// The chart and the image are taken from the resolved OCM components, so that they are updated with the root component version.
+func (c *component) GenerateBase(options components.Options) error {
+	if skip(options) {
+		return nil
+	}
+
+	chart, err := chartOptions(options.GetOCMComponents())
+	if err != nil {
+		return err
+	}
+	objects, err := helm.NewChartManifests(*chart)
+	if err != nil {
+		return err
+	}
+	return components.WriteBaseManifests(options, components.ComponentDir(c.Layer(), ComponentName), objects)
+}
+
+// GenerateLandscape generates a Flux managed overlay of the dashboard, which sets the ingress and OIDC settings and the values of the chart.
+func (c *component) GenerateLandscape(options components.Options) error {
+	if skip(options) {
+		return nil
+	}
+
+	config, err := decodeConfig(options)
+	if err != nil {
+		return err
+	}
+	configPatch, err := helm.NewValuesPatch(ComponentName, flux.GardenNamespaceName, configValues(config))
+	if err != nil {
+		return err
+	}
+	valuesPatch, err := helm.NewValuesPatch(ComponentName, flux.GardenNamespaceName, config.Values)
+	if err != nil {
+		return err
+	}
+
+	dir := components.ComponentDir(c.Layer(), ComponentName)
+	return components.WriteFluxComponent(options, dir, kustomization.FluxKustomizationOptions{
+		Name:      ComponentName,
+		SourceRef: flux.LandscapeSourceRef(options),
+	}, dir, nil, map[string][]byte{
+		ConfigPatchFileName:      configPatch,
+		helm.ValuesPatchFileName: valuesPatch,
+	})
+}
+
+// skip returns whether the component is skipped, because it is not configured or the OCM components have not been resolved.
+func skip(options components.Options) bool {
+	if options.GetComponentConfig() == nil {
+		options.GetLogger().Info("Skipping component as it is not configured", "component", ComponentName)
+		return true
+	}
+	if options.GetOCMComponents() == nil {
+		options.GetLogger().Info("Skipping component as the OCM components have not been resolved", "component", ComponentName)
+		return true
+	}
+	return false
+}
+
+// chartOptions returns the options of the dashboard chart contained in the resolved OCM components.
+// The image of the dashboard component is set in the default values of the chart.
+func chartOptions(ocmComponents *ocmcomponents.Components) (*helm.ChartOptions, error) {
+	cref, chart, err := ocmComponents.FindResource(ocmcomponents.ComponentNameDashboard, ChartResourceName, ocmcomponents.ResourceTypeHelmChart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the chart of component %q: %w", ComponentName, err)
+	}
+	repository, tag, digest, err := helm.ParseOCIReference(chart.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference of resource %s of OCM component %s: %w", ChartResourceName, cref, err)
+	}
+
+	_, image, err := ocmComponents.FindResource(ocmcomponents.ComponentNameDashboard, ImageResourceName, ocmcomponents.ResourceTypeOCIImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the image of component %q: %w", ComponentName, err)
+	}
+	imageRepository, imageTag, err := splitImageReference(image.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference of resource %s of OCM component %s: %w", ImageResourceName, cref, err)
+	}
+
+	return &helm.ChartOptions{
+		Name:            ComponentName,
+		Namespace:       flux.GardenNamespaceName,
+		Repository:      repository,
+		Version:         tag,
+		Digest:          digest,
+		TargetNamespace: flux.GardenNamespaceName,
+		Values: map[string]any{
+			"global": map[string]any{
+				"virtualGarden": map[string]any{
+					"enabled": true,
+				},
+				"dashboard": map[string]any{
+					"image": map[string]any{
+						"repository": imageRepository,
+						"tag":        imageTag,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// splitImageReference splits the given image reference into the repository and the tag, which includes the digest if there is one.
+func splitImageReference(ref string) (repository, tag string, err error) {
+	repository, digest, _ := strings.Cut(ref, "@")
+	i := strings.LastIndex(repository, ":")
+	if i <= strings.LastIndex(repository, "/") {
+		return "", "", fmt.Errorf("invalid image reference %q, a tag is required", ref)
+	}
+	repository, tag = repository[:i], repository[i+1:]
+	if digest != "" {
+		tag += "@" + digest
+	}
+	return repository, tag, nil
+}
+
+// configValues returns the values of the dashboard chart derived from the ingress and OIDC settings.
+func configValues(config *Config) map[string]any {
+	ingress := map[string]any{
+		"hosts": config.Ingress.Hosts,
+	}
+	if config.Ingress.TLSSecretName != "" {
+		ingress["tls"] = map[string]any{"secretName": config.Ingress.TLSSecretName}
+	}
+
+	return map[string]any{
+		"global": map[string]any{
+			"dashboard": map[string]any{
+				"ingress": ingress,
+				"oidc": map[string]any{
+					"issuerUrl": config.OIDC.IssuerURL,
+					"clientId":  config.OIDC.ClientID,
+				},
+			},
+		},
+	}
+}
+
+// decodeConfig decodes the configuration of the component, defaults the OIDC client ID and checks that all settings without default are given.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+	if config.OIDC.ClientID == "" {
+		config.OIDC.ClientID = defaultOIDCClientID
+	}
+
+	var missing []string
+	for field, isMissing := range map[string]bool{
+		"ingress.hosts":  len(config.Ingress.Hosts) == 0,
+		"oidc.issuerURL": config.OIDC.IssuerURL == "",
+	} {
+		if isMissing {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("configuration of component %q is missing %s", ComponentName, strings.Join(missing, ", "))
+	}
+	return config, nil
+}
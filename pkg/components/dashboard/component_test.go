@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboard_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components/dashboard"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+var _ = Describe("Dashboard Component", func() {
+	var (
+		fs afero.Afero
+
+		config = `{"ingress": {"hosts": ["dashboard.example.com"], "tlsSecretName": "dashboard-tls"}, "oidc": {"issuerURL": "https://issuer.example.com"}, "values": {"global": {"dashboard": {"oidc": {"clientSecret": "${oidc_client_secret}"}}}}}`
+	)
+
+	// resolveComponents resolves a root component referencing the dashboard component, which contains the given resources.
+	resolveComponents := func(dashboardResources ...string) *ocmcomponents.Components {
+		return test.OCMFixture{
+			Descriptors: map[ocmcomponents.ComponentReference]string{
+				ocmcomponents.ComponentReferenceFromNameAndVersion(test.RootComponent.Name, test.RootComponent.Version): test.Descriptor(test.RootComponent.Name, test.RootComponent.Version,
+					[]string{test.ComponentReference("dashboard", ocmcomponents.ComponentNameDashboard, "1.82.2")}),
+				ocmcomponents.ComponentReferenceFromNameAndVersion(ocmcomponents.ComponentNameDashboard, "1.82.2"): test.Descriptor(ocmcomponents.ComponentNameDashboard, "1.82.2",
+					nil, dashboardResources...),
+			},
+		}.Resolve()
+	}
+
+	const (
+		dashboardChart = `{"name": "gardener-dashboard", "version": "1.82.2", "type": "helmChart/v1", "relation": "external",
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/charts/gardener/dashboard:1.82.2"}}`
+		dashboardImage = `{"name": "gardener-dashboard", "version": "1.82.2", "type": "ociImage", "relation": "external",
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/gardener/dashboard:1.82.2@sha256:15ea8e737e71bd758a68e91fc618d2c565909f88f7a69ca136a99922caef35a0"}}`
+	)
+
+	// generate generates the dashboard component with the given OCM components and configuration.
+	generate := func(ocmComponents *ocmcomponents.Components, config string) error {
+		return test.Generate(test.NewOptions(fs, ocmComponents, dashboard.ComponentName, config), garden.NewComponent(), dashboard.NewComponent())
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+	})
+
+	It("should generate the chart with the image of the OCM component into the base and the configuration into the landscape", func() {
+		Expect(generate(resolveComponents(dashboardChart, dashboardImage), config)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/virtual-garden/gardener-dashboard/source.yaml")).To(ContainSubstring(`  ref:
+    tag: 1.82.2
+  url: oci://registry.example.com/charts/gardener/dashboard
+`))
+		Expect(fs.ReadFile("/repo/base/components/virtual-garden/gardener-dashboard/helmrelease.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: gardener-dashboard
+  namespace: garden
+spec:
+  chartRef:
+    kind: OCIRepository
+    name: gardener-dashboard
+    namespace: garden
+  interval: 10m0s
+  releaseName: gardener-dashboard
+  storageNamespace: garden
+  targetNamespace: garden
+  values:
+    global:
+      dashboard:
+        image:
+          repository: registry.example.com/gardener/dashboard
+          tag: 1.82.2@sha256:15ea8e737e71bd758a68e91fc618d2c565909f88f7a69ca136a99922caef35a0
+      virtualGarden:
+        enabled: true
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/gardener-dashboard/resources/config-patch.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: gardener-dashboard
+  namespace: garden
+spec:
+  values:
+    global:
+      dashboard:
+        ingress:
+          hosts:
+          - dashboard.example.com
+          tls:
+            secretName: dashboard-tls
+        oidc:
+          clientId: dashboard
+          issuerUrl: https://issuer.example.com
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/gardener-dashboard/resources/values-patch.yaml")).To(ContainSubstring("clientSecret: ${oidc_client_secret}"))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/gardener-dashboard/resources/kustomization.yaml")).To(ContainSubstring(`patches:
+  - path: config-patch.yaml
+  - path: values-patch.yaml
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/gardener-dashboard/flux-kustomization.yaml")).To(ContainSubstring(`  dependsOn:
+    - name: garden
+      namespace: flux-system
+`))
+	})
+
+	It("should skip the component if it is not configured", func() {
+		Expect(generate(resolveComponents(dashboardChart, dashboardImage), "")).To(Succeed())
+
+		Expect(fs.DirExists("/repo/base/components/virtual-garden/gardener-dashboard")).To(BeFalse())
+		Expect(fs.DirExists("/repo/landscape/components/virtual-garden/gardener-dashboard")).To(BeFalse())
+	})
+
+	It("should skip the component if the OCM components have not been resolved", func() {
+		Expect(generate(nil, config)).To(Succeed())
+
+		Expect(fs.DirExists("/repo/base/components/virtual-garden/gardener-dashboard")).To(BeFalse())
+		Expect(fs.DirExists("/repo/landscape/components/virtual-garden/gardener-dashboard")).To(BeFalse())
+	})
+
+	It("should fail if the image is not contained in the OCM components", func() {
+		Expect(generate(resolveComponents(dashboardChart), config)).To(MatchError(ContainSubstring(`failed to find the image of component "gardener-dashboard": component github.com/gardener/dashboard:1.82.2 does not contain the ociImage resource gardener-dashboard`)))
+	})
+
+	It("should fail if settings without default are missing", func() {
+		Expect(generate(resolveComponents(dashboardChart, dashboardImage), `{"oidc": {"clientID": "gardener"}}`)).To(MatchError(ContainSubstring(`configuration of component "gardener-dashboard" is missing ingress.hosts, oidc.issuerURL`)))
+	})
+})
@@ -0,0 +1,451 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	gardencorev1 "github.com/gardener/gardener/pkg/apis/core/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	operatorv1alpha1 "github.com/gardener/gardener/pkg/apis/operator/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// ComponentName is the name of the extensions component.
+	ComponentName = "extensions"
+
+	// ExtensionFileName is the name of the manifest file containing the Extension resource.
+	ExtensionFileName = "extension.yaml"
+	// ControllerRegistrationFileName is the name of the manifest file containing the ControllerRegistration resource.
+	ControllerRegistrationFileName = "controllerregistration.yaml"
+	// ControllerDeploymentFileName is the name of the manifest file containing the ControllerDeployment resource.
+	ControllerDeploymentFileName = "controllerdeployment.yaml"
+	// ValuesPatchFileName is the name of the patch file containing the landscape specific values of the extension deployment.
+	ValuesPatchFileName = "values-patch.yaml"
+
+	// ImageVectorOverwriteValuesKey is the key of the image vector overwrite in the values of the extension deployment.
+	ImageVectorOverwriteValuesKey = "imageVectorOverwrite"
+
+	// ocmComponentNamePrefix is the prefix of the names of the OCM components of the Gardener extensions.
+	ocmComponentNamePrefix = "github.com/gardener/gardener-extension-"
+)
+
+// Mode is the way an extension is registered in the landscape.
+type Mode string
+
+const (
+	// ModeExtension registers the extension with an Extension resource, which is reconciled by the gardener-operator.
+	ModeExtension Mode = "Extension"
+	// ModeControllerRegistration registers the extension with a ControllerRegistration and a ControllerDeployment resource.
+	// It is meant for landscapes which do not register their extensions via the gardener-operator yet.
+	ModeControllerRegistration Mode = "ControllerRegistration"
+)
+
+// Config is the configuration of the extensions component.
+type Config struct {
+	// Mode is the way the extensions are registered unless configured otherwise. Defaults to Extension, which only registers the
+	// configured extensions. In mode ControllerRegistration, all extensions contained in the resolved OCM components are registered.
+	Mode Mode `json:"mode,omitempty"`
+	// Extensions are the Gardener extensions deployed in the landscape. In mode ControllerRegistration, they override the settings of
+	// the extensions contained in the resolved OCM components.
+	Extensions []Extension `json:"extensions"`
+}
+
+// Extension is a Gardener extension deployed in the landscape.
+type Extension struct {
+	// Name is the name of the extension, e.g. shoot-cert-service. It is used as name of the Extension resource.
+	Name string `json:"name"`
+	// OCMComponentName is the name of the OCM component of the extension. Defaults to github.com/gardener/gardener-extension-<name>.
+	OCMComponentName string `json:"ocmComponentName,omitempty"`
+	// ChartResourceName is the name of the helm chart resource of the OCM component. Defaults to the name of the extension.
+	ChartResourceName string `json:"chartResourceName,omitempty"`
+	// Mode is the way the extension is registered. Defaults to the mode of the component.
+	Mode Mode `json:"mode,omitempty"`
+	// Disabled excludes the extension from the generation, e.g. an extension contained in the resolved OCM components, which is not
+	// used in the landscape.
+	Disabled bool `json:"disabled,omitempty"`
+	// Resources are the kinds and types of the extension resources reconciled by the extension.
+	Resources []gardencorev1beta1.ControllerResource `json:"resources,omitempty"`
+	// Values are the landscape specific values of the extension deployment.
+	Values map[string]any `json:"values,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new extensions component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
+	return nil
+}
+
+// Layer returns the rollout layer of the component.
+func (c *component) Layer() components.Layer {
+	return components.LayerExtensions
+}
+
+// FluxKustomizations returns the references to the Flux Kustomizations of the registered extensions. Components depending on the
+// extensions component depend on all of them.
+func (c *component) FluxKustomizations(options components.Options) []kustomizev1.DependencyReference {
+	extensions, err := registeredExtensions(options)
+	if err != nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var references []kustomizev1.DependencyReference
+	for _, extension := range extensions {
+		references = append(references, fluxKustomizationOptions(options, extension).DependencyReference())
+	}
+	return references
+}
+
+// GenerateBase generates an Extension resource, or a ControllerRegistration and ControllerDeployment resource depending on the mode,
+// for each registered extension into the base directory. The chart and the image vector of the extensions are taken from the resolved
+// OCM components.
+func (c *component) GenerateBase(options components.Options) error {
+	extensions, err := registeredExtensions(options)
+	if err != nil {
+		return err
+	}
+
+	for _, extension := range extensions {
+		helmDeployment, err := newHelmDeployment(extension, options.GetOCMComponents())
+		if err != nil {
+			return err
+		}
+		objects, err := newManifests(extension, helmDeployment)
+		if err != nil {
+			return err
+		}
+		if err := components.WriteBaseManifests(options, Dir(extension.Name), objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateLandscape generates a Flux managed overlay for each registered extension, which sets the values of the extension deployment.
+// ControllerRegistrations and ControllerDeployments are applied to the virtual garden, Extension resources to the runtime cluster.
+func (c *component) GenerateLandscape(options components.Options) error {
+	extensions, err := registeredExtensions(options)
+	if err != nil {
+		return err
+	}
+
+	for _, extension := range extensions {
+		valuesPatch, err := newValuesPatch(extension)
+		if err != nil {
+			return err
+		}
+
+		dir := Dir(extension.Name)
+		if err := components.WriteFluxComponent(options, dir, fluxKustomizationOptions(options, extension), dir, nil, map[string][]byte{ValuesPatchFileName: valuesPatch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registeredExtensions returns the extensions registered in the landscape, i.e. the enabled configured extensions and, in mode
+// ControllerRegistration, the extensions contained in the resolved OCM components, which are not configured. It returns no extensions
+// if the OCM components have not been resolved.
+func registeredExtensions(options components.Options) ([]Extension, error) {
+	config, err := decodeConfig(options)
+	if err != nil || (config.Mode == ModeExtension && len(config.Extensions) == 0) {
+		return nil, err
+	}
+	ocmComponents := options.GetOCMComponents()
+	if ocmComponents == nil {
+		logSkipped(options)
+		return nil, nil
+	}
+
+	var (
+		extensions        []Extension
+		ocmComponentNames = sets.New[string]()
+	)
+	for _, extension := range config.Extensions {
+		ocmComponentNames.Insert(extension.OCMComponentName)
+		if !extension.Disabled {
+			extensions = append(extensions, extension)
+		}
+	}
+	if config.Mode != ModeControllerRegistration {
+		return extensions, nil
+	}
+
+	for _, cref := range ocmComponents.GetSortedComponents() {
+		ocmComponentName, _, err := cref.ExtractNameAndVersion()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(ocmComponentName, ocmComponentNamePrefix) || ocmComponentNames.Has(ocmComponentName) {
+			continue
+		}
+		ocmComponentNames.Insert(ocmComponentName)
+
+		name := strings.TrimPrefix(ocmComponentName, ocmComponentNamePrefix)
+		if errs := utilvalidation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf("extension of OCM component %s has an invalid name %q, it can be disabled in the configuration of component %q: %s",
+				cref, name, ComponentName, strings.Join(errs, ", "))
+		}
+		extensions = append(extensions, Extension{
+			Name:              name,
+			OCMComponentName:  ocmComponentName,
+			ChartResourceName: name,
+			Mode:              ModeControllerRegistration,
+		})
+	}
+	return extensions, nil
+}
+
+// fluxKustomizationOptions returns the options of the Flux Kustomization managing the given extension. ControllerRegistrations and
+// ControllerDeployments are applied to the virtual garden.
+func fluxKustomizationOptions(options components.Options, extension Extension) kustomization.FluxKustomizationOptions {
+	fluxOpts := kustomization.FluxKustomizationOptions{
+		Name:      FluxKustomizationName(extension.Name),
+		SourceRef: flux.LandscapeSourceRef(options),
+	}
+	if extension.Mode == ModeControllerRegistration {
+		fluxOpts = garden.TargetVirtualGarden(fluxOpts)
+	}
+	return fluxOpts
+}
+
+// Dir returns the directory of the extension with the given name.
+func Dir(name string) string {
+	return components.ComponentDir(components.LayerExtensions, name)
+}
+
+// FluxKustomizationName returns the name of the Flux Kustomization managing the extension with the given name.
+func FluxKustomizationName(name string) string {
+	return "extension-" + name
+}
+
+// helmDeployment contains the chart and the default values of an extension deployment.
+type helmDeployment struct {
+	chartRef string
+	values   *apiextensionsv1.JSON
+}
+
+// newHelmDeployment returns the deployment of the given extension with the chart of its OCM component.
+// The image vector of the OCM component is passed as image vector overwrite to the extension deployment.
+func newHelmDeployment(extension Extension, ocmComponents *ocmcomponents.Components) (*helmDeployment, error) {
+	cref, chart, err := ocmComponents.FindResource(extension.OCMComponentName, extension.ChartResourceName, ocmcomponents.ResourceTypeHelmChart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the chart of extension %q: %w", extension.Name, err)
+	}
+	deployment := &helmDeployment{chartRef: chart.Value}
+
+	imageVectorOverwrite, err := ocmComponents.GetImageVectorOverwrite(cref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image vector of OCM component %s: %w", cref, err)
+	}
+	if imageVectorOverwrite != "" {
+		values, err := json.Marshal(map[string]any{ImageVectorOverwriteValuesKey: imageVectorOverwrite})
+		if err != nil {
+			return nil, err
+		}
+		deployment.values = &apiextensionsv1.JSON{Raw: values}
+	}
+	return deployment, nil
+}
+
+// newManifests returns the manifests registering the given extension according to its mode.
+func newManifests(extension Extension, deployment *helmDeployment) (map[string][]byte, error) {
+	if extension.Mode == ModeControllerRegistration {
+		controllerRegistration, err := yaml.Marshal(newControllerRegistration(extension))
+		if err != nil {
+			return nil, err
+		}
+		controllerDeployment, err := yaml.Marshal(newControllerDeployment(extension, deployment))
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{
+			ControllerRegistrationFileName: controllerRegistration,
+			ControllerDeploymentFileName:   controllerDeployment,
+		}, nil
+	}
+
+	content, err := yaml.Marshal(newExtension(extension, deployment))
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{ExtensionFileName: content}, nil
+}
+
+// newExtension returns the Extension resource of the given extension.
+func newExtension(extension Extension, deployment *helmDeployment) *operatorv1alpha1.Extension {
+	return &operatorv1alpha1.Extension{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Extension",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: extension.Name,
+		},
+		Spec: operatorv1alpha1.ExtensionSpec{
+			Resources: extension.Resources,
+			Deployment: &operatorv1alpha1.Deployment{ExtensionDeployment: &operatorv1alpha1.ExtensionDeploymentSpec{
+				DeploymentSpec: operatorv1alpha1.DeploymentSpec{
+					Helm: &operatorv1alpha1.ExtensionHelm{
+						OCIRepository: &gardencorev1.OCIRepository{Ref: ptr.To(deployment.chartRef)},
+					},
+				},
+				Values: deployment.values,
+			}},
+		},
+	}
+}
+
+// newControllerRegistration returns the ControllerRegistration resource of the given extension, which refers to the ControllerDeployment
+// of the same name.
+func newControllerRegistration(extension Extension) *gardencorev1beta1.ControllerRegistration {
+	return &gardencorev1beta1.ControllerRegistration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardencorev1beta1.SchemeGroupVersion.String(),
+			Kind:       "ControllerRegistration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: extension.Name,
+		},
+		Spec: gardencorev1beta1.ControllerRegistrationSpec{
+			Resources: extension.Resources,
+			Deployment: &gardencorev1beta1.ControllerRegistrationDeployment{
+				DeploymentRefs: []gardencorev1beta1.DeploymentRef{{Name: extension.Name}},
+			},
+		},
+	}
+}
+
+// newControllerDeployment returns the ControllerDeployment resource of the given extension.
+func newControllerDeployment(extension Extension, deployment *helmDeployment) *gardencorev1.ControllerDeployment {
+	return &gardencorev1.ControllerDeployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardencorev1.SchemeGroupVersion.String(),
+			Kind:       "ControllerDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: extension.Name,
+		},
+		Helm: &gardencorev1.HelmControllerDeployment{
+			OCIRepository: &gardencorev1.OCIRepository{Ref: ptr.To(deployment.chartRef)},
+			Values:        deployment.values,
+		},
+	}
+}
+
+// newValuesPatch returns a patch setting the values of the given extension in the resource deploying it.
+func newValuesPatch(extension Extension) ([]byte, error) {
+	values := extension.Values
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	if extension.Mode == ModeControllerRegistration {
+		return yaml.Marshal(map[string]any{
+			"apiVersion": gardencorev1.SchemeGroupVersion.String(),
+			"kind":       "ControllerDeployment",
+			"metadata": map[string]any{
+				"name": extension.Name,
+			},
+			"helm": map[string]any{
+				"values": values,
+			},
+		})
+	}
+
+	return yaml.Marshal(map[string]any{
+		"apiVersion": operatorv1alpha1.SchemeGroupVersion.String(),
+		"kind":       "Extension",
+		"metadata": map[string]any{
+			"name": extension.Name,
+		},
+		"spec": map[string]any{
+			"deployment": map[string]any{
+				"extension": map[string]any{
+					"values": values,
+				},
+			},
+		},
+	})
+}
+
+// decodeConfig decodes and validates the configuration of the component. The modes, OCM component and chart names of the extensions are defaulted.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+
+	switch config.Mode {
+	case "":
+		config.Mode = ModeExtension
+	case ModeExtension, ModeControllerRegistration:
+	default:
+		return nil, fmt.Errorf("component %q has unsupported mode %q, supported modes are %s and %s",
+			ComponentName, config.Mode, ModeExtension, ModeControllerRegistration)
+	}
+
+	names := sets.New[string]()
+	for i := range config.Extensions {
+		extension := &config.Extensions[i]
+		if extension.Name == "" {
+			return nil, fmt.Errorf("extension %d of component %q is missing name", i, ComponentName)
+		}
+		if errs := utilvalidation.IsDNS1123Label(extension.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("extension %d of component %q has an invalid name %q: %s", i, ComponentName, extension.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(extension.Name) {
+			return nil, fmt.Errorf("extension %q of component %q is configured more than once", extension.Name, ComponentName)
+		}
+		names.Insert(extension.Name)
+
+		switch extension.Mode {
+		case "":
+			extension.Mode = config.Mode
+		case ModeExtension, ModeControllerRegistration:
+		default:
+			return nil, fmt.Errorf("extension %q of component %q has unsupported mode %q, supported modes are %s and %s",
+				extension.Name, ComponentName, extension.Mode, ModeExtension, ModeControllerRegistration)
+		}
+		if extension.OCMComponentName == "" {
+			extension.OCMComponentName = ocmComponentNamePrefix + extension.Name
+		}
+		if extension.ChartResourceName == "" {
+			extension.ChartResourceName = extension.Name
+		}
+	}
+	return config, nil
+}
+
+func logSkipped(options components.Options) {
+	options.GetLogger().Info("Skipping component as the OCM components have not been resolved", "component", ComponentName)
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extensions_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components/extensions"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+var _ = Describe("Extensions Component", func() {
+	var (
+		fs            afero.Afero
+		ocmComponents *ocmcomponents.Components
+	)
+
+	// resolveComponents resolves the OCM components of the test data.
+	resolveComponents := func(originalRefs bool) *ocmcomponents.Components {
+		fixture := test.TestdataFixture("../../ocm/components/testdata")
+		fixture.OriginalRefs = originalRefs
+		return fixture.Resolve()
+	}
+
+	// generate generates the extensions component with the given OCM components and configuration.
+	generate := func(ocmComponents *ocmcomponents.Components, config string) error {
+		return test.Generate(test.NewOptions(fs, ocmComponents, extensions.ComponentName, config), extensions.NewComponent())
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		ocmComponents = resolveComponents(false)
+	})
+
+	It("should generate the Extension resources from the OCM components into the base and a values overlay into the landscape", func() {
+		Expect(generate(ocmComponents, `{"extensions": [{
+  "name": "shoot-cert-service",
+  "resources": [{"kind": "Extension", "type": "shoot-cert-service"}],
+  "values": {"certificateConfig": {"defaultIssuer": {"name": "garden"}}}
+}]}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/extensions/shoot-cert-service/extension.yaml")).To(MatchYAML(`apiVersion: operator.gardener.cloud/v1alpha1
+kind: Extension
+metadata:
+  name: shoot-cert-service
+spec:
+  deployment:
+    extension:
+      helm:
+        ociRepository:
+          ref: registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/charts/gardener/extensions/shoot-cert-service:v1.53.0@sha256:1236fb136e6951d2c438d6ae315721425f866fc494e2d811582b43c0a579e90e
+      values:
+        imageVectorOverwrite: |
+          images:
+          - name: cert-management
+            repository: registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/cert-controller-manager
+            tag: v0.17.7@sha256:6f55f7bf5a6498dc0d138e5cde33eb39a090ceeee1fe80647008cb8e04676d8c
+            version: v0.17.7
+  resources:
+  - kind: Extension
+    type: shoot-cert-service
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/extensions/shoot-cert-service/resources/values-patch.yaml")).To(MatchYAML(`apiVersion: operator.gardener.cloud/v1alpha1
+kind: Extension
+metadata:
+  name: shoot-cert-service
+spec:
+  deployment:
+    extension:
+      values:
+        certificateConfig:
+          defaultIssuer:
+            name: garden
+`))
+		content, err := fs.ReadFile("/repo/landscape/components/extensions/shoot-cert-service/flux-kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("name: extension-shoot-cert-service"))
+		Expect(string(content)).To(ContainSubstring("path: landscape/components/extensions/shoot-cert-service/resources"))
+		Expect(string(content)).NotTo(ContainSubstring("kubeConfig"))
+	})
+
+	It("should generate a ControllerRegistration and ControllerDeployment if configured", func() {
+		Expect(generate(ocmComponents, `{"extensions": [{
+  "name": "shoot-cert-service",
+  "mode": "ControllerRegistration",
+  "resources": [{"kind": "Extension", "type": "shoot-cert-service"}],
+  "values": {"certificateConfig": {"defaultIssuer": {"name": "garden"}}}
+}]}`)).To(Succeed())
+
+		Expect(fs.Exists("/repo/base/components/extensions/shoot-cert-service/extension.yaml")).To(BeFalse())
+		Expect(fs.ReadFile("/repo/base/components/extensions/shoot-cert-service/controllerregistration.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1beta1
+kind: ControllerRegistration
+metadata:
+  name: shoot-cert-service
+spec:
+  deployment:
+    deploymentRefs:
+    - name: shoot-cert-service
+  resources:
+  - kind: Extension
+    type: shoot-cert-service
+`))
+		Expect(fs.ReadFile("/repo/base/components/extensions/shoot-cert-service/controllerdeployment.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1
+kind: ControllerDeployment
+metadata:
+  name: shoot-cert-service
+helm:
+  ociRepository:
+    ref: registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/charts/gardener/extensions/shoot-cert-service:v1.53.0@sha256:1236fb136e6951d2c438d6ae315721425f866fc494e2d811582b43c0a579e90e
+  values:
+    imageVectorOverwrite: |
+      images:
+      - name: cert-management
+        repository: registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/cert-controller-manager
+        tag: v0.17.7@sha256:6f55f7bf5a6498dc0d138e5cde33eb39a090ceeee1fe80647008cb8e04676d8c
+        version: v0.17.7
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/extensions/shoot-cert-service/resources/values-patch.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1
+kind: ControllerDeployment
+metadata:
+  name: shoot-cert-service
+helm:
+  values:
+    certificateConfig:
+      defaultIssuer:
+        name: garden
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/extensions/shoot-cert-service/flux-kustomization.yaml")).To(ContainSubstring(`  kubeConfig:
+    secretRef:
+      key: kubeconfig
+      name: gardener-internal
+`))
+	})
+
+	Context("with several extensions in the OCM components", func() {
+		BeforeEach(func() {
+			var (
+				descriptors         = map[ocmcomponents.ComponentReference]string{}
+				componentReferences []string
+			)
+			for _, name := range []string{"networking-calico", "os-gardenlinux", "provider-aws", "shoot-dns-service"} {
+				ocmComponentName := "github.com/gardener/gardener-extension-" + name
+				descriptors[ocmcomponents.ComponentReferenceFromNameAndVersion(ocmComponentName, "v1.0.0")] = test.Descriptor(ocmComponentName, "v1.0.0", nil,
+					fmt.Sprintf(`{"name": %[1]q, "version": "v1.0.0", "type": "helmChart/v1", "relation": "external",
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/charts/%[1]s:v1.0.0"}}`, name))
+				componentReferences = append(componentReferences, test.ComponentReference(name, ocmComponentName, "v1.0.0"))
+			}
+			descriptors[ocmcomponents.ComponentReferenceFromNameAndVersion(test.RootComponent.Name, test.RootComponent.Version)] = test.Descriptor(
+				test.RootComponent.Name, test.RootComponent.Version, componentReferences)
+
+			ocmComponents = test.OCMFixture{Descriptors: descriptors}.Resolve()
+		})
+
+		It("should register all extensions of the OCM components in mode ControllerRegistration unless configured otherwise", func() {
+			Expect(generate(ocmComponents, `{"mode": "ControllerRegistration", "extensions": [
+  {"name": "networking-calico", "mode": "Extension"},
+  {"name": "provider-aws", "resources": [{"kind": "Infrastructure", "type": "aws"}], "values": {"replicaCount": 2}},
+  {"name": "shoot-dns-service", "disabled": true}
+]}`)).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/base/components/extensions/provider-aws/controllerregistration.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1beta1
+kind: ControllerRegistration
+metadata:
+  name: provider-aws
+spec:
+  deployment:
+    deploymentRefs:
+    - name: provider-aws
+  resources:
+  - kind: Infrastructure
+    type: aws
+`))
+			Expect(fs.ReadFile("/repo/landscape/components/extensions/provider-aws/resources/values-patch.yaml")).To(ContainSubstring("replicaCount: 2"))
+
+			Expect(fs.ReadFile("/repo/base/components/extensions/os-gardenlinux/controllerregistration.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1beta1
+kind: ControllerRegistration
+metadata:
+  name: os-gardenlinux
+spec:
+  deployment:
+    deploymentRefs:
+    - name: os-gardenlinux
+`))
+			Expect(fs.ReadFile("/repo/base/components/extensions/os-gardenlinux/controllerdeployment.yaml")).To(MatchYAML(`apiVersion: core.gardener.cloud/v1
+kind: ControllerDeployment
+metadata:
+  name: os-gardenlinux
+helm:
+  ociRepository:
+    ref: registry.example.com/charts/os-gardenlinux:v1.0.0
+`))
+			Expect(fs.ReadFile("/repo/landscape/components/extensions/os-gardenlinux/flux-kustomization.yaml")).To(ContainSubstring("  name: extension-os-gardenlinux\n  namespace: garden\n"))
+
+			Expect(fs.Exists("/repo/base/components/extensions/networking-calico/extension.yaml")).To(BeTrue())
+			Expect(fs.Exists("/repo/base/components/extensions/networking-calico/controllerregistration.yaml")).To(BeFalse())
+			Expect(fs.DirExists("/repo/base/components/extensions/shoot-dns-service")).To(BeFalse())
+			Expect(fs.DirExists("/repo/landscape/components/extensions/shoot-dns-service")).To(BeFalse())
+		})
+
+		It("should only register the configured extensions in mode Extension", func() {
+			Expect(generate(ocmComponents, `{"extensions": [{"name": "provider-aws"}]}`)).To(Succeed())
+
+			Expect(fs.Exists("/repo/base/components/extensions/provider-aws/extension.yaml")).To(BeTrue())
+			for _, name := range []string{"networking-calico", "os-gardenlinux", "shoot-dns-service"} {
+				Expect(fs.DirExists("/repo/base/components/extensions/" + name)).To(BeFalse())
+			}
+		})
+	})
+
+	It("should use the original image references if they were resolved", func() {
+		Expect(generate(resolveComponents(true), `{"extensions": [{"name": "shoot-cert-service"}]}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/extensions/shoot-cert-service/extension.yaml")).To(ContainSubstring("repository: europe-docker.pkg.dev/gardener-project/releases/cert-controller-manager\n"))
+	})
+
+	It("should skip the extensions if the OCM components have not been resolved", func() {
+		Expect(generate(nil, `{"extensions": [{"name": "shoot-cert-service"}]}`)).To(Succeed())
+
+		Expect(fs.DirExists("/repo/base/components/extensions/shoot-cert-service")).To(BeFalse())
+		Expect(fs.DirExists("/repo/landscape/components/extensions/shoot-cert-service")).To(BeFalse())
+	})
+
+	It("should fail for extensions that are not contained in the OCM components", func() {
+		Expect(generate(ocmComponents, `{"extensions": [{"name": "provider-aws"}]}`)).To(MatchError(ContainSubstring(
+			`failed to find the chart of extension "provider-aws": component github.com/gardener/gardener-extension-provider-aws is not contained in the resolved components`)))
+		Expect(generate(ocmComponents, `{"extensions": [{"name": "shoot-cert-service", "chartResourceName": "admission"}]}`)).To(MatchError(ContainSubstring(
+			`failed to find the chart of extension "shoot-cert-service": component github.com/gardener/gardener-extension-shoot-cert-service:v1.53.0 does not contain the helmChart/v1 resource admission`)))
+	})
+
+	It("should fail for invalid extension configurations", func() {
+		Expect(generate(ocmComponents, `{"extensions": [{}]}`)).To(MatchError(ContainSubstring(`extension 0 of component "extensions" is missing name`)))
+		Expect(generate(ocmComponents, `{"extensions": [{"name": "Provider_AWS"}]}`)).To(MatchError(ContainSubstring(`extension 0 of component "extensions" has an invalid name "Provider_AWS"`)))
+		Expect(generate(ocmComponents, `{"extensions": [{"name": "a"}, {"name": "a"}]}`)).To(MatchError(ContainSubstring(`extension "a" of component "extensions" is configured more than once`)))
+		Expect(generate(ocmComponents, `{"extensions": [{"name": "a", "mode": "Unknown"}]}`)).To(MatchError(ContainSubstring(
+			`extension "a" of component "extensions" has unsupported mode "Unknown", supported modes are Extension and ControllerRegistration`)))
+		Expect(generate(ocmComponents, `{"mode": "Unknown"}`)).To(MatchError(ContainSubstring(
+			`component "extensions" has unsupported mode "Unknown", supported modes are Extension and ControllerRegistration`)))
+	})
+})
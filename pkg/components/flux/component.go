@@ -6,19 +6,27 @@ package flux
 
 import (
 	"embed"
+	"maps"
 	"path"
+	"slices"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev2 "github.com/fluxcd/source-controller/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
 	"github.com/gardener/gardener-landscape-kit/pkg/components"
 	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
 	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
 )
 
 const (
+	// ComponentName is the name of the Flux component.
+	ComponentName = "flux"
+
 	// DirName is the directory name where the cluster instances are stored.
 	DirName = "flux"
 
@@ -37,6 +45,11 @@ const (
 )
 
 var (
+	// baseTemplateDir is the directory where the base templates are stored.
+	baseTemplateDir = "templates/base"
+	//go:embed templates/base
+	baseTemplates embed.FS
+
 	// landscapeTemplateDir is the directory where the landscape templates are stored.
 	landscapeTemplateDir = "templates/landscape"
 	//go:embed templates/landscape
@@ -45,23 +58,44 @@ var (
 
 type component struct{}
 
-// NewComponent creates a new gardener-operator component.
+// NewComponent creates a new flux component.
 func NewComponent() components.Interface {
 	return &component{}
 }
 
-// GenerateBase generates the component base directory.
-func (c *component) GenerateBase(_ components.Options) error {
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
 	return nil
 }
 
+// Layer returns the rollout layer of the component. Flux is bootstrapped manually and not assigned to any layer.
+func (c *component) Layer() components.Layer {
+	return components.LayerNone
+}
+
+// GenerateBase generates the component base directory.
+func (c *component) GenerateBase(options components.Options) error {
+	objects, err := readTemplates(baseTemplates, baseTemplateDir)
+	if err != nil {
+		return err
+	}
+	return components.WriteBaseManifests(options, FluxComponentsDirName, objects)
+}
+
 // GenerateLandscape generates the component landscape directory.
 func (c *component) GenerateLandscape(options components.Options) error {
 	for _, op := range []func(components.Options) error{
 		writeFluxTemplateFilesAndKustomization,
 		writeGitignoreFile,
-		writeGardenNamespaceManifest, // The `garden` namespace will hold all Flux resources (related to gardener components) in the cluster and must be created as soon as possible.
+		writeNamespaceManifests, // The namespaces, e.g. `garden` holding all Flux resources of the Gardener components, must be created as soon as possible.
+		writeVariablesConfigMap,
 		writeFluxKustomization,
+		writeLayerFluxKustomizations,
 		logFluxInitializationFirstSteps,
 	} {
 		if err := op(options); err != nil {
@@ -71,37 +105,210 @@ func (c *component) GenerateLandscape(options components.Options) error {
 	return nil
 }
 
+// writeFluxTemplateFilesAndKustomization writes the landscape templates as an overlay of the Flux components in the base directory.
+// The Git sync secret is written next to the overlay but not referenced, as it should not be checked into the landscape Git repo.
 func writeFluxTemplateFilesAndKustomization(options components.Options) error {
-	var (
-		objects                    = make(map[string][]byte)
-		kustomizationObjectEntries []string
-	)
-	dir, err := landscapeTemplates.ReadDir(landscapeTemplateDir)
+	objects, err := readTemplates(landscapeTemplates, landscapeTemplateDir)
 	if err != nil {
 		return err
 	}
-	for _, file := range dir {
-		fileName := file.Name()
-		if fileName == gitignoreTemplateFile {
-			continue
-		}
-		if fileName != gitSecretFileName {
-			kustomizationObjectEntries = append(kustomizationObjectEntries, fileName)
-		}
-		fileContents, err := landscapeTemplates.ReadFile(path.Join(landscapeTemplateDir, fileName))
+	delete(objects, gitignoreTemplateFile)
+
+	gitSecret := map[string][]byte{gitSecretFileName: objects[gitSecretFileName]}
+	delete(objects, gitSecretFileName)
+	if err := files.WriteObjectsToFilesystem(gitSecret, options.GetLandscapeDir(), FluxComponentsDirName, options.GetFilesystem()); err != nil {
+		return err
+	}
+
+	baseSourceObjects, patches, err := baseSourceManifests(options)
+	if err != nil {
+		return err
+	}
+	maps.Copy(objects, baseSourceObjects)
+
+	baseDir := FluxComponentsDirName
+	if options.GetConfig().BaseSource != nil {
+		// Flux is bootstrapped from the landscape repository before the base source is available, hence the Flux components are
+		// written into the landscape directly instead of being referenced from the base source.
+		baseObjects, err := readTemplates(baseTemplates, baseTemplateDir)
 		if err != nil {
 			return err
 		}
-		objects[fileName] = fileContents
+		maps.Copy(objects, baseObjects)
+		baseDir = ""
 	}
 
-	kustomizationManifest := kustomization.NewKustomization(kustomizationObjectEntries, nil)
-	objects[kustomization.KustomizationFileName], err = yaml.Marshal(kustomizationManifest)
-	if err != nil {
+	return components.WriteLandscapeOverlay(options, FluxComponentsDirName, baseDir, objects, patches)
+}
+
+const (
+	// BaseSourceFileName is the name of the manifest file containing the GitRepository or OCIRepository of the base source.
+	BaseSourceFileName = "base-source.yaml"
+	// baseSourceIncludePatchFileName is the name of the patch including a Git base source into the Flux system repository.
+	baseSourceIncludePatchFileName = "base-source-include-patch.yaml"
+	// landscapeArtifactGeneratorFileName is the name of the manifest file containing the ArtifactGenerator, which composes the
+	// landscape repository and an OCI base source.
+	landscapeArtifactGeneratorFileName = "landscape-artifact-generator.yaml"
+
+	// artifactGeneratorAPIVersion is the API version of the ArtifactGenerator served by the Flux source-watcher.
+	artifactGeneratorAPIVersion = "source.extensions.fluxcd.io/v1beta1"
+	// artifactGeneratorKind is the kind of the ArtifactGenerator.
+	artifactGeneratorKind = "ArtifactGenerator"
+)
+
+// WriteBaseSource writes the GitRepository or OCIRepository of the configured base source into the landscape directory.
+// Its checksums are updated, so that the change is not reported by the next generation.
+func WriteBaseSource(options components.Options) error {
+	objects, _, err := baseSourceManifests(options)
+	if err != nil || len(objects) == 0 {
 		return err
 	}
+	if err := files.WriteObjectsToFilesystem(objects, options.GetLandscapeDir(), FluxComponentsDirName, options.GetFilesystem()); err != nil {
+		return err
+	}
+
+	var relativePaths []string
+	for _, fileName := range slices.Sorted(maps.Keys(objects)) {
+		relativePaths = append(relativePaths, path.Join(FluxComponentsDirName, fileName))
+	}
+	return files.UpdateChecksums(options.GetLandscapeDir(), options.GetFilesystem(), relativePaths...)
+}
+
+// baseSourceManifests returns the manifests of the configured base source and the patches, which make the base source available in the
+// glk-base directory of the source the landscape overlays are applied from, see LandscapeSourceRef. It returns nothing if no base
+// source is configured.
+func baseSourceManifests(options components.Options) (map[string][]byte, map[string][]byte, error) {
+	baseSource := options.GetConfig().BaseSource
+	if baseSource == nil {
+		return nil, nil, nil
+	}
+	if baseSource.IsOCI() {
+		return ociBaseSourceManifests(baseSource)
+	}
+
+	gitRepository := &sourcev2.GitRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev2.GroupVersion.String(),
+			Kind:       sourcev2.GitRepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      components.BaseSourceDirName,
+			Namespace: kustomization.FluxSystemNamespaceName,
+		},
+		Spec: sourcev2.GitRepositorySpec{
+			URL:      baseSource.URL,
+			Interval: metav1.Duration{Duration: kustomization.DefaultFluxInterval},
+			Reference: &sourcev2.GitRepositoryRef{
+				Tag:    baseSource.Ref.Tag,
+				Commit: baseSource.Ref.Commit,
+			},
+		},
+	}
+	if baseSource.SecretRefName != "" {
+		gitRepository.Spec.SecretRef = &fluxmeta.LocalObjectReference{Name: baseSource.SecretRefName}
+	}
+	gitRepositoryContent, err := yaml.Marshal(gitRepository)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	includePatchContent, err := yaml.Marshal(map[string]any{
+		"apiVersion": sourcev2.GroupVersion.String(),
+		"kind":       sourcev2.GitRepositoryKind,
+		"metadata": map[string]any{
+			"name":      FluxSystemRepositoryName,
+			"namespace": kustomization.FluxSystemNamespaceName,
+		},
+		"spec": map[string]any{
+			"include": []sourcev2.GitRepositoryInclude{{
+				GitRepositoryRef: fluxmeta.LocalObjectReference{Name: components.BaseSourceDirName},
+				FromPath:         baseSource.Path,
+				ToPath:           components.BaseSourceDirName,
+			}},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return map[string][]byte{BaseSourceFileName: gitRepositoryContent}, map[string][]byte{baseSourceIncludePatchFileName: includePatchContent}, nil
+}
+
+// ociBaseSourceManifests returns the OCIRepository of the given base source and the ArtifactGenerator composing the Flux system repository
+// and the base source into the ExternalArtifact referenced by LandscapeSourceRef. The ArtifactGenerator is served by the Flux
+// source-watcher, which has to be installed in addition to the default Flux controllers.
+func ociBaseSourceManifests(baseSource *configv1alpha1.BaseSource) (map[string][]byte, map[string][]byte, error) {
+	ociRepository := &sourcev2.OCIRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev2.GroupVersion.String(),
+			Kind:       sourcev2.OCIRepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      components.BaseSourceDirName,
+			Namespace: kustomization.FluxSystemNamespaceName,
+		},
+		Spec: sourcev2.OCIRepositorySpec{
+			URL:      baseSource.URL,
+			Interval: metav1.Duration{Duration: kustomization.DefaultFluxInterval},
+			Reference: &sourcev2.OCIRepositoryRef{
+				Tag:    baseSource.Ref.Tag,
+				Digest: baseSource.Ref.Digest,
+			},
+		},
+	}
+	if baseSource.SecretRefName != "" {
+		ociRepository.Spec.SecretRef = &fluxmeta.LocalObjectReference{Name: baseSource.SecretRefName}
+	}
+	ociRepositoryContent, err := yaml.Marshal(ociRepository)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	artifactGeneratorContent, err := yaml.Marshal(map[string]any{
+		"apiVersion": artifactGeneratorAPIVersion,
+		"kind":       artifactGeneratorKind,
+		"metadata": map[string]any{
+			"name":      LandscapeArtifactName,
+			"namespace": kustomization.FluxSystemNamespaceName,
+		},
+		"spec": map[string]any{
+			"sources": []map[string]any{
+				{"alias": "landscape", "kind": sourcev2.GitRepositoryKind, "name": FluxSystemRepositoryName},
+				{"alias": "base", "kind": sourcev2.OCIRepositoryKind, "name": components.BaseSourceDirName},
+			},
+			"artifacts": []map[string]any{{
+				"name": LandscapeArtifactName,
+				"copy": []map[string]any{
+					{"from": "@landscape/**", "to": "@artifact/"},
+					{"from": path.Join("@base", baseSource.Path, "**"), "to": "@artifact/" + components.BaseSourceDirName + "/"},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return files.WriteObjectsToFilesystem(objects, options.GetLandscapeDir(), FluxComponentsDirName, options.GetFilesystem())
+	return map[string][]byte{
+		BaseSourceFileName:                 ociRepositoryContent,
+		landscapeArtifactGeneratorFileName: artifactGeneratorContent,
+	}, nil, nil
+}
+
+func readTemplates(templates embed.FS, templateDir string) (map[string][]byte, error) {
+	dir, err := templates.ReadDir(templateDir)
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[string][]byte, len(dir))
+	for _, file := range dir {
+		fileContents, err := templates.ReadFile(path.Join(templateDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		objects[file.Name()] = fileContents
+	}
+	return objects, nil
 }
 
 func writeGitignoreFile(options components.Options) error {
@@ -121,21 +328,12 @@ func writeGitignoreFile(options components.Options) error {
 }
 
 func writeFluxKustomization(options components.Options) error {
-	relativeLandscapeDir := files.ComputeBasePath(options.GetLandscapeDir(), options.GetBaseDir())
-	k, err := yaml.Marshal(&kustomizev1.Kustomization{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: kustomizev1.GroupVersion.String(),
-			Kind:       kustomizev1.KustomizationKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      glkComponentsName,
-			Namespace: FluxSystemNamespaceName,
-		},
-		Spec: kustomizev1.KustomizationSpec{
-			SourceRef: SourceRef,
-			Path:      path.Join(relativeLandscapeDir, components.DirName),
-		},
-	})
+	relativeLandscapeDir := components.LandscapeRepositoryPath(options)
+	k, err := yaml.Marshal(kustomization.NewFluxKustomization(kustomization.FluxKustomizationOptions{
+		Name:      glkComponentsName,
+		Namespace: kustomization.FluxSystemNamespaceName,
+		SourceRef: LandscapeSourceRef(options),
+	}, path.Join(relativeLandscapeDir, components.DirName)))
 	if err != nil {
 		return err
 	}
@@ -147,12 +345,46 @@ func writeFluxKustomization(options components.Options) error {
 	)
 }
 
+// writeLayerFluxKustomizations writes a Flux Kustomization for each layer of the components tree, which depends on the previous layer.
+// A layer only becomes ready once all Flux Kustomizations of its components are ready.
+func writeLayerFluxKustomizations(options components.Options) error {
+	relativeLandscapeDir := components.LandscapeRepositoryPath(options)
+
+	var dependsOn []kustomizev1.DependencyReference
+	for _, layer := range components.Layers {
+		k, err := yaml.Marshal(kustomization.NewFluxKustomization(kustomization.FluxKustomizationOptions{
+			Name:      layer.FluxKustomizationName(),
+			SourceRef: LandscapeSourceRef(options),
+			Prune:     true,
+			Wait:      true,
+			DependsOn: dependsOn,
+		}, path.Join(relativeLandscapeDir, layer.Dir())))
+		if err != nil {
+			return err
+		}
+		if err := files.WriteObjectsToFilesystem(
+			map[string][]byte{kustomization.FluxKustomizationFileName: k},
+			options.GetLandscapeDir(),
+			layer.Dir(),
+			options.GetFilesystem(),
+		); err != nil {
+			return err
+		}
+		dependsOn = []kustomizev1.DependencyReference{{Name: layer.FluxKustomizationName()}}
+	}
+	return nil
+}
+
 func logFluxInitializationFirstSteps(options components.Options) error {
 	landscapeDir := options.GetLandscapeDir()
 	if instanceFileExisted, err := options.GetFilesystem().DirExists(path.Join(landscapeDir, FluxComponentsDirName)); err != nil || instanceFileExisted {
 		return err
 	}
 	fluxDir := path.Join(landscapeDir, DirName)
+	fluxComponentsDir := path.Join(options.GetBaseDir(), FluxComponentsDirName)
+	if options.GetConfig().BaseSource != nil {
+		fluxComponentsDir = path.Join(landscapeDir, FluxComponentsDirName)
+	}
 	options.GetLogger().Info(`Initialized the landscape for an expected Flux cluster at: ` + fluxDir + `
 
 Next steps:
@@ -166,7 +398,7 @@ Next steps:
 
 3. Install the Flux CRDs initially:
 
-   $  kubectl create -f ` + path.Join(landscapeDir, FluxComponentsDirName, "gotk-components.yaml") + `
+   $  kubectl create -f ` + path.Join(fluxComponentsDir, "gotk-components.yaml") + `
 
 4. You might want to consider creating the Git sync credentials manually and store them separately instead of checking them into Git:
 
@@ -181,31 +413,66 @@ Next steps:
 	return nil
 }
 
+// GardenNamespaceName is the name of the namespace holding the Flux resources of the Gardener components.
+const GardenNamespaceName = "garden"
+
+// Namespaces returns the namespaces required by the component.
+func (c *component) Namespaces(_ components.Options) []components.Namespace {
+	return []components.Namespace{{Name: GardenNamespaceName, PodSecurityLevel: components.PodSecurityLevelPrivileged}}
+}
+
+// NamespaceFileName returns the name of the manifest file of the namespace with the given name.
+func NamespaceFileName(name string) string {
+	return name + "-namespace.yaml"
+}
+
+// writeNamespaceManifests writes the namespaces declared by all components into the Flux directory, so that they are created
+// before any component is rolled out.
+func writeNamespaceManifests(options components.Options) error {
+	objects := make(map[string][]byte, len(options.GetNamespaces()))
+	for _, namespace := range options.GetNamespaces() {
+		content, err := yaml.Marshal(namespace.Object())
+		if err != nil {
+			return err
+		}
+		objects[NamespaceFileName(namespace.Name)] = content
+	}
+
+	return files.WriteObjectsToFilesystem(objects, options.GetLandscapeDir(), DirName, options.GetFilesystem())
+}
+
 const (
-	// NamespaceKind is the kind of the namespace resource.
-	NamespaceKind = "Namespace"
-	// GardenNamespaceFileName is the name of the namespace manifest file.
-	GardenNamespaceFileName = "garden-namespace.yaml"
-	// GardenNamespaceName is the name of the namespace created by this component.
-	GardenNamespaceName = "garden"
+	// VariablesFileName is the name of the manifest file containing the landscape-wide variables.
+	VariablesFileName = "variables.yaml"
+	// GardenVariablesFileName is the name of the manifest file containing the landscape-wide variables for the Flux Kustomizations in the
+	// garden namespace, see GardenNamespaceName.
+	GardenVariablesFileName = "garden-variables.yaml"
 )
 
-// writeGardenNamespaceManifest generates the garden namespace in the given landscape directory.
-func writeGardenNamespaceManifest(options components.Options) error {
-	objects := make(map[string][]byte)
-
-	var err error
-	objects[GardenNamespaceFileName], err = yaml.Marshal(&corev1.Namespace{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: corev1.SchemeGroupVersion.String(),
-			Kind:       NamespaceKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: GardenNamespaceName,
-		},
-	})
-	if err != nil {
-		return err
+// writeVariablesConfigMap writes the landscape-wide variables of the configuration to the ConfigMaps referenced by all generated Flux
+// Kustomizations for post-build substitution. A ConfigMap is written into each namespace holding Flux Kustomizations, i.e. flux-system
+// and garden, even if no variables are configured.
+func writeVariablesConfigMap(options components.Options) error {
+	objects := make(map[string][]byte, 2)
+	for fileName, namespace := range map[string]string{
+		VariablesFileName:       kustomization.FluxSystemNamespaceName,
+		GardenVariablesFileName: GardenNamespaceName,
+	} {
+		content, err := yaml.Marshal(&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kustomization.VariablesConfigMapName,
+				Namespace: namespace,
+			},
+			Data: options.GetConfig().Variables,
+		})
+		if err != nil {
+			return err
+		}
+		objects[fileName] = content
 	}
 
 	return files.WriteObjectsToFilesystem(objects, options.GetLandscapeDir(), DirName, options.GetFilesystem())
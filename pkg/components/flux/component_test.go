@@ -5,11 +5,14 @@
 package flux_test
 
 import (
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
 
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
 	"github.com/gardener/gardener-landscape-kit/pkg/components"
 	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
 )
@@ -22,7 +25,19 @@ var _ = Describe("Flux Component Generation", func() {
 
 	BeforeEach(func() {
 		fs = afero.Afero{Fs: afero.NewMemMapFs()}
-		opts = components.NewOptions("/baseDir", "/landscapeDir", fs, logr.Discard())
+		opts = components.NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, fs, logr.Discard())
+	})
+
+	Describe("#GenerateBase", func() {
+		It("should generate the flux components into the base directory", func() {
+			component := flux.NewComponent()
+			Expect(component.GenerateBase(opts)).To(Succeed())
+
+			Expect(fs.Exists("/baseDir/flux/flux-system/gotk-components.yaml")).To(BeTrue())
+			content, err := fs.ReadFile("/baseDir/flux/flux-system/kustomization.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("- gotk-components.yaml"))
+		})
 	})
 
 	Describe("#GenerateLandscape", func() {
@@ -31,6 +46,214 @@ var _ = Describe("Flux Component Generation", func() {
 			Expect(component.GenerateLandscape(opts)).To(Succeed())
 		})
 
+		It("should reference the flux components of the base directory", func() {
+			component := flux.NewComponent()
+			Expect(component.GenerateLandscape(opts)).To(Succeed())
+
+			Expect(fs.Exists("/landscapeDir/flux/flux-system/gotk-components.yaml")).To(BeFalse())
+			Expect(fs.Exists("/landscapeDir/flux/flux-system/git-sync-secret.yaml")).To(BeTrue())
+			content, err := fs.ReadFile("/landscapeDir/flux/flux-system/kustomization.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - ../../../baseDir/flux/flux-system
+  - gotk-sync.yaml
+`))
+		})
+
+		It("should generate the layer Flux Kustomizations depending on the previous layer", func() {
+			component := flux.NewComponent()
+			Expect(component.GenerateLandscape(opts)).To(Succeed())
+
+			var previous *kustomizev1.Kustomization
+			for _, layer := range components.Layers {
+				content, err := fs.ReadFile("/landscapeDir/components/" + string(layer) + "/flux-kustomization.yaml")
+				Expect(err).NotTo(HaveOccurred())
+
+				k := &kustomizev1.Kustomization{}
+				Expect(yaml.Unmarshal(content, k)).To(Succeed())
+				Expect(k.Name).To(Equal("glk-" + string(layer)))
+				Expect(k.Spec.Path).To(Equal("landscapeDir/components/" + string(layer)))
+				Expect(k.Spec.Wait).To(BeTrue())
+				if previous == nil {
+					Expect(k.Spec.DependsOn).To(BeEmpty())
+				} else {
+					Expect(k.Spec.DependsOn).To(ConsistOf(kustomizev1.DependencyReference{Name: previous.Name}))
+				}
+				previous = k
+			}
+		})
+
+		It("should generate the variables ConfigMaps for the flux-system and garden namespaces", func() {
+			opts = components.NewOptions("/baseDir", nil, "/landscapeDir", &configv1alpha1.LandscapeKitConfiguration{
+				Variables: map[string]string{"domain": "example.com"},
+			}, nil, fs, logr.Discard())
+
+			component := flux.NewComponent()
+			Expect(component.GenerateLandscape(opts)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscapeDir/flux/variables.yaml")).To(MatchYAML(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: glk-variables
+  namespace: flux-system
+data:
+  domain: example.com
+`))
+			Expect(fs.ReadFile("/landscapeDir/flux/garden-variables.yaml")).To(MatchYAML(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: glk-variables
+  namespace: garden
+data:
+  domain: example.com
+`))
+		})
+
+		It("should include the configured base source into the Flux system repository", func() {
+			opts = components.NewOptions("/baseDir", nil, "/landscapeDir", &configv1alpha1.LandscapeKitConfiguration{
+				BaseSource: &configv1alpha1.BaseSource{
+					URL:  "https://example.com/base",
+					Ref:  configv1alpha1.BaseSourceRef{Tag: "v1.0.0"},
+					Path: "base",
+				},
+			}, nil, fs, logr.Discard())
+
+			component := flux.NewComponent()
+			Expect(component.GenerateLandscape(opts)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscapeDir/flux/flux-system/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- base-source.yaml
+- gotk-components.yaml
+- gotk-sync.yaml
+patches:
+- path: base-source-include-patch.yaml
+`))
+			Expect(fs.ReadFile("/landscapeDir/flux/flux-system/base-source-include-patch.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  include:
+  - repository:
+      name: glk-base
+    fromPath: base
+    toPath: glk-base
+`))
+			content, err := fs.ReadFile("/landscapeDir/flux/flux-system/base-source.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("tag: v1.0.0"))
+
+			content, err = fs.ReadFile("/landscapeDir/flux/glk-components.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("path: components\n"))
+
+			opts.GetConfig().BaseSource.Ref = configv1alpha1.BaseSourceRef{Tag: "v1.1.0"}
+			Expect(flux.WriteBaseSource(opts)).To(Succeed())
+
+			content, err = fs.ReadFile("/landscapeDir/flux/flux-system/base-source.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("tag: v1.1.0"))
+		})
+
+		It("should compose the landscape repository and an OCI base source into an artifact", func() {
+			opts = components.NewOptions("/baseDir", nil, "/landscapeDir", &configv1alpha1.LandscapeKitConfiguration{
+				BaseSource: &configv1alpha1.BaseSource{
+					URL:           "oci://example.com/base",
+					Ref:           configv1alpha1.BaseSourceRef{Digest: "sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b"},
+					Path:          "base",
+					SecretRefName: "registry",
+				},
+			}, nil, fs, logr.Discard())
+
+			component := flux.NewComponent()
+			Expect(component.GenerateLandscape(opts)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscapeDir/flux/flux-system/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- base-source.yaml
+- gotk-components.yaml
+- gotk-sync.yaml
+- landscape-artifact-generator.yaml
+`))
+			Expect(fs.ReadFile("/landscapeDir/flux/flux-system/base-source.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: glk-base
+  namespace: flux-system
+spec:
+  interval: 10m0s
+  ref:
+    digest: sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b
+  secretRef:
+    name: registry
+  url: oci://example.com/base
+status: {}
+`))
+			Expect(fs.ReadFile("/landscapeDir/flux/flux-system/landscape-artifact-generator.yaml")).To(MatchYAML(`apiVersion: source.extensions.fluxcd.io/v1beta1
+kind: ArtifactGenerator
+metadata:
+  name: glk-landscape
+  namespace: flux-system
+spec:
+  sources:
+  - alias: landscape
+    kind: GitRepository
+    name: flux-system
+  - alias: base
+    kind: OCIRepository
+    name: glk-base
+  artifacts:
+  - name: glk-landscape
+    copy:
+    - from: '@landscape/**'
+      to: '@artifact/'
+    - from: '@base/base/**'
+      to: '@artifact/glk-base/'
+`))
+
+			content, err := fs.ReadFile("/landscapeDir/flux/glk-components.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			k := &kustomizev1.Kustomization{}
+			Expect(yaml.Unmarshal(content, k)).To(Succeed())
+			Expect(k.Spec.SourceRef).To(Equal(kustomizev1.CrossNamespaceSourceReference{Kind: "ExternalArtifact", Name: "glk-landscape", Namespace: "flux-system"}))
+			Expect(k.Spec.Path).To(Equal("components"))
+		})
+
+		It("should write the namespaces declared by all components", func() {
+			opts = components.NewOptions("/baseDir", nil, "/landscapeDir", &configv1alpha1.LandscapeKitConfiguration{
+				Namespaces: []configv1alpha1.NamespaceConfig{{Name: "monitoring", Labels: map[string]string{"team": "ops"}}},
+			}, nil, fs, logr.Discard())
+			registry := components.NewRegistry(1, false)
+			Expect(registry.RegisterComponent(flux.NewComponent())).To(Succeed())
+
+			Expect(registry.Generate(opts)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscapeDir/flux/garden-namespace.yaml")).To(MatchYAML(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: garden
+  labels:
+    pod-security.kubernetes.io/enforce: privileged
+spec: {}
+status: {}
+`))
+			Expect(fs.ReadFile("/landscapeDir/flux/monitoring-namespace.yaml")).To(MatchYAML(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: monitoring
+  labels:
+    team: ops
+spec: {}
+status: {}
+`))
+		})
+
 		It("should not recreate a deleted gitignore file", func() {
 			component := flux.NewComponent()
 			Expect(component.GenerateLandscape(opts)).To(Succeed())
@@ -7,19 +7,36 @@ package flux
 import (
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	sourcev2 "github.com/fluxcd/source-controller/api/v1"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
 )
 
 const (
 	// FluxSystemRepositoryName is the name of the Flux system repository.
 	FluxSystemRepositoryName = "flux-system"
 
-	// FluxSystemNamespaceName is the name of the namespace used by Flux components.
-	FluxSystemNamespaceName = "flux-system"
+	// LandscapeArtifactName is the name of the ExternalArtifact the landscape repository and an OCI base source are composed into.
+	LandscapeArtifactName = "glk-landscape"
 )
 
 // SourceRef is the reference to the repository containing the Flux installation and manifests.
 var SourceRef = kustomizev1.CrossNamespaceSourceReference{
 	Kind:      sourcev2.GitRepositoryKind,
 	Name:      FluxSystemRepositoryName,
-	Namespace: FluxSystemNamespaceName,
+	Namespace: kustomization.FluxSystemNamespaceName,
+}
+
+// LandscapeSourceRef returns the reference to the source the Flux Kustomizations of the components apply the landscape manifests from.
+// It is the Flux system repository, unless an OCI base source is configured. As OCI artifacts cannot be included into a Git repository,
+// the landscape repository and the base source are composed into an ExternalArtifact in that case.
+func LandscapeSourceRef(options components.Options) kustomizev1.CrossNamespaceSourceReference {
+	if baseSource := options.GetConfig().BaseSource; baseSource != nil && baseSource.IsOCI() {
+		return kustomizev1.CrossNamespaceSourceReference{
+			Kind:      sourcev2.ExternalArtifactKind,
+			Name:      LandscapeArtifactName,
+			Namespace: kustomization.FluxSystemNamespaceName,
+		}
+	}
+	return SourceRef
 }
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package garden
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	fluxkustomize "github.com/fluxcd/pkg/apis/kustomize"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	operatorv1alpha1 "github.com/gardener/gardener/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// ComponentName is the name of the Garden component.
+	ComponentName = "garden"
+
+	// GardenName is the name of the generated Garden resource.
+	GardenName = "garden"
+	// GardenFileName is the name of the manifest file containing the Garden resource.
+	GardenFileName = "garden.yaml"
+
+	// VirtualGardenAccessSecretName is the name of the secret in the garden namespace of the runtime cluster, which is managed by the
+	// gardener-operator and contains a kubeconfig for the virtual garden cluster using its cluster-internal address.
+	VirtualGardenAccessSecretName = "gardener-internal"
+	// VirtualGardenAccessSecretKey is the key of the kubeconfig in the virtual garden access secret.
+	VirtualGardenAccessSecretKey = "kubeconfig"
+
+	// defaultIngressControllerKind is the kind of the ingress controller deployed into the runtime cluster.
+	defaultIngressControllerKind = "nginx"
+)
+
+// gardenHealthCheck evaluates the health of the Garden resource, which does not report a Ready condition.
+var gardenHealthCheck = fluxkustomize.CustomHealthCheck{
+	APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
+	Kind:       "Garden",
+	HealthCheckExpressions: fluxkustomize.HealthCheckExpressions{
+		Current: "status.observedGeneration == metadata.generation && status.lastOperation.state == 'Succeeded'",
+		Failed:  "status.lastOperation.state == 'Failed'",
+	},
+}
+
+// defaultMaintenanceTimeWindow is the maintenance time window of the virtual cluster if none is configured.
+var defaultMaintenanceTimeWindow = gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"}
+
+// Config is the configuration of the Garden component.
+type Config struct {
+	// RuntimeCluster contains the settings of the runtime cluster hosting the virtual garden.
+	RuntimeCluster RuntimeCluster `json:"runtimeCluster"`
+	// VirtualCluster contains the settings of the virtual garden cluster.
+	VirtualCluster VirtualCluster `json:"virtualCluster"`
+	// DNS contains the DNS providers managing the records of the runtime and virtual cluster domains.
+	DNS *operatorv1alpha1.DNSManagement `json:"dns,omitempty"`
+	// ETCDBackup contains the settings of the backup of the main ETCD of the virtual cluster.
+	ETCDBackup *operatorv1alpha1.Backup `json:"etcdBackup,omitempty"`
+	// HighAvailability enables the high availability of the control plane of the virtual cluster.
+	HighAvailability bool `json:"highAvailability,omitempty"`
+}
+
+// RuntimeCluster contains the settings of the runtime cluster.
+type RuntimeCluster struct {
+	// Networking contains the CIDRs of the runtime cluster.
+	Networking operatorv1alpha1.RuntimeNetworking `json:"networking"`
+	// IngressDomains are the wildcard domains of the ingress controller of the runtime cluster.
+	IngressDomains []operatorv1alpha1.DNSDomain `json:"ingressDomains"`
+	// Provider contains the region and zones of the runtime cluster.
+	Provider operatorv1alpha1.Provider `json:"provider,omitempty"`
+}
+
+// VirtualCluster contains the settings of the virtual garden cluster.
+type VirtualCluster struct {
+	// Domains are the domains of the API server of the virtual cluster.
+	Domains []operatorv1alpha1.DNSDomain `json:"domains"`
+	// Networking contains the CIDRs of the virtual cluster.
+	Networking operatorv1alpha1.Networking `json:"networking"`
+	// KubernetesVersion is the Kubernetes version of the virtual cluster.
+	KubernetesVersion string `json:"kubernetesVersion"`
+	// ClusterIdentity is the identity of the Gardener installation, it must be unique across all landscapes.
+	ClusterIdentity string `json:"clusterIdentity"`
+	// MaintenanceTimeWindow is the maintenance time window of the virtual cluster. Defaults to 22:00-23:00 UTC.
+	MaintenanceTimeWindow *gardencorev1beta1.MaintenanceTimeWindow `json:"maintenanceTimeWindow,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new Garden component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
+	return nil
+}
+
+// Layer returns the rollout layer of the component. It is rolled out after the gardener-operator of the runtime layer.
+func (c *component) Layer() components.Layer {
+	return components.LayerVirtualGarden
+}
+
+// Secrets returns the secrets of the DNS providers and the ETCD backup referenced by the Garden, which are applied together with the
+// Garden into the garden namespace of the runtime cluster.
+func (c *component) Secrets(options components.Options) []components.Secret {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		// The error is reported when generating the landscape.
+		return nil
+	}
+
+	var (
+		secrets []components.Secret
+		names   = sets.New[string]()
+	)
+	addSecret := func(name, description string) {
+		if name == "" || names.Has(name) {
+			return
+		}
+		names.Insert(name)
+		secrets = append(secrets, components.Secret{Name: name, Namespace: flux.GardenNamespaceName, Description: description})
+	}
+	if config.DNS != nil {
+		for _, provider := range config.DNS.Providers {
+			addSecret(provider.SecretRef.Name, fmt.Sprintf("Credentials of the DNS provider %q of type %s.", provider.Name, provider.Type))
+		}
+	}
+	if config.ETCDBackup != nil {
+		addSecret(config.ETCDBackup.SecretRef.Name, fmt.Sprintf("Credentials of the %s object store the ETCD of the virtual cluster is backed up to.", config.ETCDBackup.Provider))
+	}
+	return secrets
+}
+
+// GenerateBase does nothing, the Garden resource only consists of landscape specific settings.
+func (c *component) GenerateBase(_ components.Options) error {
+	return nil
+}
+
+// GenerateLandscape generates the Garden resource from the component configuration into a Flux managed directory of the landscape.
+// The Flux Kustomization waits for the Garden to be ready, so that components depending on it can access the virtual garden.
+// If secrets are configured, the referenced secrets are scaffolded next to the Garden, see Secrets.
+func (c *component) GenerateLandscape(options components.Options) error {
+	if options.GetComponentConfig() == nil {
+		options.GetLogger().Info("Skipping component as it is not configured", "component", ComponentName)
+		return nil
+	}
+
+	config, err := decodeConfig(options)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(NewGarden(config))
+	if err != nil {
+		return err
+	}
+
+	return components.WriteFluxComponent(options, components.ComponentDir(c.Layer(), ComponentName), kustomization.FluxKustomizationOptions{
+		Name:             ComponentName,
+		SourceRef:        flux.LandscapeSourceRef(options),
+		Wait:             true,
+		HealthCheckExprs: []fluxkustomize.CustomHealthCheck{gardenHealthCheck},
+	}, "", map[string][]byte{GardenFileName: content}, nil)
+}
+
+// TargetVirtualGarden returns the given Flux Kustomization options modified to apply the resources to the virtual garden cluster
+// instead of the runtime cluster. The Flux Kustomization is placed into the garden namespace to use the virtual garden access secret.
+// Components using it must be rolled out after the Garden is ready, i.e. depend on this component or belong to a later layer.
+func TargetVirtualGarden(opts kustomization.FluxKustomizationOptions) kustomization.FluxKustomizationOptions {
+	opts.Namespace = flux.GardenNamespaceName
+	opts.KubeConfigSecretRef = &fluxmeta.SecretKeyReference{
+		Name: VirtualGardenAccessSecretName,
+		Key:  VirtualGardenAccessSecretKey,
+	}
+	return opts
+}
+
+// NewGarden returns the Garden resource for the given configuration.
+func NewGarden(config *Config) *operatorv1alpha1.Garden {
+	garden := &operatorv1alpha1.Garden{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Garden",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GardenName,
+		},
+		Spec: operatorv1alpha1.GardenSpec{
+			DNS: config.DNS,
+			RuntimeCluster: operatorv1alpha1.RuntimeCluster{
+				Ingress: operatorv1alpha1.Ingress{
+					Domains:    config.RuntimeCluster.IngressDomains,
+					Controller: gardencorev1beta1.IngressController{Kind: defaultIngressControllerKind},
+				},
+				Networking: config.RuntimeCluster.Networking,
+				Provider:   config.RuntimeCluster.Provider,
+			},
+			VirtualCluster: operatorv1alpha1.VirtualCluster{
+				DNS: operatorv1alpha1.DNS{
+					Domains: config.VirtualCluster.Domains,
+				},
+				Gardener: operatorv1alpha1.Gardener{
+					ClusterIdentity: config.VirtualCluster.ClusterIdentity,
+				},
+				Kubernetes: operatorv1alpha1.Kubernetes{
+					Version: config.VirtualCluster.KubernetesVersion,
+				},
+				Maintenance: operatorv1alpha1.Maintenance{
+					TimeWindow: defaultMaintenanceTimeWindow,
+				},
+				Networking: config.VirtualCluster.Networking,
+			},
+		},
+	}
+
+	if config.VirtualCluster.MaintenanceTimeWindow != nil {
+		garden.Spec.VirtualCluster.Maintenance.TimeWindow = *config.VirtualCluster.MaintenanceTimeWindow
+	}
+	if config.ETCDBackup != nil {
+		garden.Spec.VirtualCluster.ETCD = &operatorv1alpha1.ETCD{
+			Main: &operatorv1alpha1.ETCDMain{Backup: config.ETCDBackup},
+		}
+	}
+	if config.HighAvailability {
+		garden.Spec.VirtualCluster.ControlPlane = &operatorv1alpha1.ControlPlane{
+			HighAvailability: &operatorv1alpha1.HighAvailability{},
+		}
+	}
+	return garden
+}
+
+// decodeConfig decodes the configuration of the component and checks that all settings without default are given.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for field, isMissing := range map[string]bool{
+		"runtimeCluster.networking.pods":     len(config.RuntimeCluster.Networking.Pods) == 0,
+		"runtimeCluster.networking.services": len(config.RuntimeCluster.Networking.Services) == 0,
+		"runtimeCluster.ingressDomains":      len(config.RuntimeCluster.IngressDomains) == 0,
+		"virtualCluster.domains":             len(config.VirtualCluster.Domains) == 0,
+		"virtualCluster.networking.services": len(config.VirtualCluster.Networking.Services) == 0,
+		"virtualCluster.kubernetesVersion":   config.VirtualCluster.KubernetesVersion == "",
+		"virtualCluster.clusterIdentity":     config.VirtualCluster.ClusterIdentity == "",
+	} {
+		if isMissing {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("configuration of component %q is missing %s", ComponentName, strings.Join(missing, ", "))
+	}
+	return config, nil
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package garden_test
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+)
+
+var _ = Describe("Garden Component", func() {
+	var (
+		fs            afero.Afero
+		secretsConfig *configv1alpha1.SecretsConfig
+	)
+
+	newOptions := func(config string) components.Options {
+		var componentConfigs []configv1alpha1.ComponentConfig
+		if config != "" {
+			componentConfigs = append(componentConfigs, configv1alpha1.ComponentConfig{Name: garden.ComponentName, Config: &runtime.RawExtension{Raw: []byte(config)}})
+		}
+		return components.NewOptions("/repo/base", nil, "/repo/landscape", &configv1alpha1.LandscapeKitConfiguration{
+			Components: componentConfigs,
+			Secrets:    secretsConfig,
+		}, nil, fs, logr.Discard())
+	}
+
+	generate := func(opts components.Options) error {
+		registry := components.NewRegistry(1, false)
+		Expect(registry.RegisterComponent(flux.NewComponent())).To(Succeed())
+		Expect(registry.RegisterComponent(garden.NewComponent())).To(Succeed())
+		return registry.Generate(opts)
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		secretsConfig = nil
+	})
+
+	It("should generate the Garden resource from the configuration into the virtual-garden layer", func() {
+		Expect(generate(newOptions(`{
+  "runtimeCluster": {
+    "networking": {"pods": ["10.1.0.0/16"], "services": ["10.2.0.0/16"], "nodes": ["10.0.0.0/16"]},
+    "ingressDomains": [{"name": "ingress.runtime.example.com", "provider": "primary"}],
+    "provider": {"region": "eu-west-1", "zones": ["eu-west-1a", "eu-west-1b"]}
+  },
+  "virtualCluster": {
+    "domains": [{"name": "garden.example.com", "provider": "primary"}],
+    "networking": {"services": ["100.64.0.0/13"]},
+    "kubernetesVersion": "1.33.4",
+    "clusterIdentity": "landscape-dev"
+  },
+  "dns": {"providers": [{"name": "primary", "type": "aws-route53", "secretRef": {"name": "dns-credentials"}}]},
+  "etcdBackup": {"provider": "aws", "bucketName": "garden-backup", "region": "eu-west-1", "secretRef": {"name": "backup-credentials"}},
+  "highAvailability": true
+}`))).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/resources/garden.yaml")).To(MatchYAML(`apiVersion: operator.gardener.cloud/v1alpha1
+kind: Garden
+metadata:
+  name: garden
+spec:
+  dns:
+    providers:
+    - name: primary
+      secretRef:
+        name: dns-credentials
+      type: aws-route53
+  runtimeCluster:
+    ingress:
+      controller:
+        kind: nginx
+      domains:
+      - name: ingress.runtime.example.com
+        provider: primary
+    networking:
+      nodes:
+      - 10.0.0.0/16
+      pods:
+      - 10.1.0.0/16
+      services:
+      - 10.2.0.0/16
+    provider:
+      region: eu-west-1
+      zones:
+      - eu-west-1a
+      - eu-west-1b
+  virtualCluster:
+    controlPlane:
+      highAvailability: {}
+    dns:
+      domains:
+      - name: garden.example.com
+        provider: primary
+    etcd:
+      main:
+        backup:
+          bucketName: garden-backup
+          provider: aws
+          region: eu-west-1
+          secretRef:
+            name: backup-credentials
+    gardener:
+      clusterIdentity: landscape-dev
+    kubernetes:
+      version: 1.33.4
+    maintenance:
+      timeWindow:
+        begin: 220000+0000
+        end: 230000+0000
+    networking:
+      services:
+      - 100.64.0.0/13
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/resources/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- garden.yaml
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/flux-kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: garden
+  namespace: flux-system
+spec:
+  healthCheckExprs:
+  - apiVersion: operator.gardener.cloud/v1alpha1
+    current: status.observedGeneration == metadata.generation && status.lastOperation.state == 'Succeeded'
+    failed: status.lastOperation.state == 'Failed'
+    kind: Garden
+  interval: 10m0s
+  path: landscape/components/virtual-garden/garden/resources
+  postBuild:
+    substituteFrom:
+    - kind: ConfigMap
+      name: glk-variables
+      optional: true
+  prune: false
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+    namespace: flux-system
+  wait: true
+status: {}
+`))
+		Expect(fs.DirExists("/repo/base/components/virtual-garden/garden")).To(BeFalse())
+	})
+
+	It("should scaffold the secrets of the DNS providers and the ETCD backup if secrets are configured", func() {
+		secretsConfig = &configv1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}}
+
+		Expect(generate(newOptions(`{
+  "runtimeCluster": {"networking": {"pods": ["10.1.0.0/16"], "services": ["10.2.0.0/16"]}, "ingressDomains": [{"name": "ingress.runtime.example.com"}]},
+  "virtualCluster": {"domains": [{"name": "garden.example.com"}], "networking": {"services": ["100.64.0.0/13"]}, "kubernetesVersion": "1.33.4", "clusterIdentity": "landscape-dev"},
+  "dns": {"providers": [
+    {"name": "primary", "type": "aws-route53", "secretRef": {"name": "dns-credentials"}},
+    {"name": "secondary", "type": "aws-route53", "secretRef": {"name": "dns-credentials"}}
+  ]},
+  "etcdBackup": {"provider": "aws", "secretRef": {"name": "backup-credentials"}}
+}`))).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/resources/secret-dns-credentials.sops.yaml")).To(HavePrefix(`# Credentials of the DNS provider "primary" of type aws-route53.
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/resources/secret-backup-credentials.sops.yaml")).To(ContainSubstring(`# Credentials of the aws object store the ETCD of the virtual cluster is backed up to.
+# Fill in the stringData and encrypt the file before committing it: sops --encrypt --in-place secret-backup-credentials.sops.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: backup-credentials
+  namespace: garden
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/resources/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- garden.yaml
+- secret-dns-credentials.sops.yaml
+- secret-backup-credentials.sops.yaml
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/virtual-garden/garden/flux-kustomization.yaml")).To(ContainSubstring(`  decryption:
+    provider: sops
+    secretRef:
+      name: sops-age
+`))
+		Expect(fs.Exists("/repo/landscape/.sops.yaml")).To(BeTrue())
+	})
+
+	It("should skip the component if it is not configured", func() {
+		Expect(generate(newOptions(""))).To(Succeed())
+
+		Expect(fs.DirExists("/repo/landscape/components/virtual-garden/garden")).To(BeFalse())
+	})
+
+	It("should fail if required settings are missing", func() {
+		Expect(generate(newOptions(`{"virtualCluster": {"kubernetesVersion": "1.33.4"}}`))).To(MatchError(ContainSubstring(`configuration of component "garden" is missing runtimeCluster.ingressDomains, runtimeCluster.networking.pods, runtimeCluster.networking.services, virtualCluster.clusterIdentity, virtualCluster.domains, virtualCluster.networking.services`)))
+	})
+
+	It("should reject unknown settings", func() {
+		Expect(generate(newOptions(`{"virtualCluster": {"unknown": true}}`))).To(MatchError(ContainSubstring(`unknown field "unknown"`)))
+	})
+})
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardeneroperator
+
+import (
+	"fmt"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/helm"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// ComponentName is the name of the gardener-operator component.
+	ComponentName = "gardener-operator"
+	// ChartResourceName is the name of the OCM resource containing the gardener-operator chart.
+	ChartResourceName = "operator"
+	// ImageVectorOverwriteValuesKey is the key of the image vector overwrite in the values of the gardener-operator chart.
+	ImageVectorOverwriteValuesKey = "imageVectorOverwrite"
+	// FeatureHighAvailability is the name of the feature running the gardener-operator with multiple replicas.
+	FeatureHighAvailability = "high-availability"
+)
+
+// Config is the configuration of the gardener-operator component.
+type Config struct {
+	// Values are the values of the gardener-operator chart in the landscape.
+	Values map[string]any `json:"values,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new gardener-operator component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
+	return nil
+}
+
+// Layer returns the rollout layer of the component.
+func (c *component) Layer() components.Layer {
+	return components.LayerRuntime
+}
+
+// Namespaces returns the namespaces required by the component.
+func (c *component) Namespaces(_ components.Options) []components.Namespace {
+	return []components.Namespace{{Name: flux.GardenNamespaceName, PodSecurityLevel: components.PodSecurityLevelPrivileged}}
+}
+
+// GenerateBase generates the chart source and the HelmRelease of the gardener-operator into the base directory.
+// The chart is taken from the resolved OCM components, so that it is updated with the root component version. The image vector of
+// the Gardener component is passed as image vector overwrite to the gardener-operator.
+// Landscapes can enable the high-availability feature to run the gardener-operator with multiple replicas.
+func (c *component) GenerateBase(options components.Options) error {
+	chart, err := chartOptions(options)
+	if err != nil || chart == nil {
+		return err
+	}
+
+	objects, err := helm.NewChartManifests(*chart)
+	if err != nil {
+		return err
+	}
+	dir := components.ComponentDir(c.Layer(), ComponentName)
+	if err := components.WriteBaseManifests(options, dir, objects); err != nil {
+		return err
+	}
+
+	highAvailabilityPatch, err := helm.NewValuesPatch(ComponentName, flux.GardenNamespaceName, map[string]any{"replicaCount": 2})
+	if err != nil {
+		return err
+	}
+	return components.WriteBaseFeature(options, dir, FeatureHighAvailability, nil, map[string][]byte{helm.ValuesPatchFileName: highAvailabilityPatch})
+}
+
+// GenerateLandscape generates a Flux managed overlay of the gardener-operator, which sets the values of the chart.
+func (c *component) GenerateLandscape(options components.Options) error {
+	if options.GetOCMComponents() == nil {
+		logSkipped(options)
+		return nil
+	}
+
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return err
+	}
+	valuesPatch, err := helm.NewValuesPatch(ComponentName, flux.GardenNamespaceName, config.Values)
+	if err != nil {
+		return err
+	}
+
+	dir := components.ComponentDir(c.Layer(), ComponentName)
+	return components.WriteFluxComponent(options, dir, kustomization.FluxKustomizationOptions{
+		Name:      ComponentName,
+		SourceRef: flux.LandscapeSourceRef(options),
+		Wait:      true,
+	}, dir, nil, map[string][]byte{helm.ValuesPatchFileName: valuesPatch})
+}
+
+// chartOptions returns the options of the gardener-operator chart contained in the resolved OCM components.
+// It returns nil if the OCM components have not been resolved.
+func chartOptions(options components.Options) (*helm.ChartOptions, error) {
+	ocmComponents := options.GetOCMComponents()
+	if ocmComponents == nil {
+		logSkipped(options)
+		return nil, nil
+	}
+
+	cref, resource, err := ocmComponents.FindResource(ocmcomponents.ComponentNameGardener, ChartResourceName, ocmcomponents.ResourceTypeHelmChart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the chart of component %q: %w", ComponentName, err)
+	}
+
+	repository, tag, digest, err := helm.ParseOCIReference(resource.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference of resource %s of OCM component %s: %w", ChartResourceName, cref, err)
+	}
+	chart := &helm.ChartOptions{
+		Name:            ComponentName,
+		Namespace:       flux.GardenNamespaceName,
+		Repository:      repository,
+		Version:         tag,
+		Digest:          digest,
+		TargetNamespace: flux.GardenNamespaceName,
+	}
+
+	imageVectorOverwrite, err := ocmComponents.GetImageVectorOverwrite(cref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image vector of OCM component %s: %w", cref, err)
+	}
+	if imageVectorOverwrite != "" {
+		chart.Values = map[string]any{ImageVectorOverwriteValuesKey: imageVectorOverwrite}
+	}
+	return chart, nil
+}
+
+func logSkipped(options components.Options) {
+	options.GetLogger().Info("Skipping component as the OCM components have not been resolved", "component", ComponentName)
+}
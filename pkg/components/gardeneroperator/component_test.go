@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardeneroperator_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/gardeneroperator"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+var _ = Describe("Gardener Operator Component", func() {
+	var (
+		fs           afero.Afero
+		originalRefs bool
+	)
+
+	// resolveComponents resolves a root component referencing the given Gardener version, which contains the given resources.
+	resolveComponents := func(gardenerVersion string, gardenerResources ...string) *ocmcomponents.Components {
+		return test.OCMFixture{
+			Descriptors: map[ocmcomponents.ComponentReference]string{
+				ocmcomponents.ComponentReferenceFromNameAndVersion(test.RootComponent.Name, test.RootComponent.Version): test.Descriptor(test.RootComponent.Name, test.RootComponent.Version,
+					[]string{test.ComponentReference("gardener", ocmcomponents.ComponentNameGardener, gardenerVersion)}),
+				ocmcomponents.ComponentReferenceFromNameAndVersion(ocmcomponents.ComponentNameGardener, gardenerVersion): test.Descriptor(ocmcomponents.ComponentNameGardener, gardenerVersion,
+					nil, gardenerResources...),
+			},
+			OriginalRefs: originalRefs,
+		}.Resolve()
+	}
+
+	operatorChart := func(version string) string {
+		return fmt.Sprintf(`{"name": "operator", "version": %[1]q, "type": "helmChart/v1", "relation": "external",
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/charts/gardener/operator:%[1]s@sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b"}}`, version)
+	}
+
+	const operatorImage = `{"name": "operator", "version": "v1.128.3", "type": "ociImage", "relation": "external",
+  "labels": [{"name": "imagevector.gardener.cloud/name", "value": "gardener-operator"},
+    {"name": "cloud.gardener.cnudie/migration/original_ref", "value": "europe-docker.pkg.dev/gardener-project/releases/gardener/operator:v1.128.3"}],
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/gardener/operator:v1.128.3"}}`
+
+	// generate generates the gardener-operator component with the given OCM components and configuration.
+	generate := func(ocmComponents *ocmcomponents.Components, config string) error {
+		return test.Generate(test.NewOptions(fs, ocmComponents, gardeneroperator.ComponentName, config), gardeneroperator.NewComponent())
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		originalRefs = false
+	})
+
+	It("should generate the chart of the resolved Gardener version into the base and a values overlay into the landscape", func() {
+		Expect(generate(resolveComponents("v1.128.3", operatorChart("v1.128.3"), operatorImage), `{"values": {"replicaCount": 2}}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/source.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: gardener-operator
+  namespace: garden
+spec:
+  interval: 10m0s
+  layerSelector:
+    mediaType: application/vnd.cncf.helm.chart.content.v1.tar+gzip
+    operation: copy
+  ref:
+    digest: sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b
+    tag: v1.128.3
+  url: oci://registry.example.com/charts/gardener/operator
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/helmrelease.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: gardener-operator
+  namespace: garden
+spec:
+  chartRef:
+    kind: OCIRepository
+    name: gardener-operator
+    namespace: garden
+  interval: 10m0s
+  releaseName: gardener-operator
+  storageNamespace: garden
+  targetNamespace: garden
+  values:
+    imageVectorOverwrite: |
+      images:
+      - name: gardener-operator
+        repository: registry.example.com/gardener/operator
+        tag: v1.128.3
+        version: v1.128.3
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/runtime/gardener-operator/resources/values-patch.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: gardener-operator
+  namespace: garden
+spec:
+  values:
+    replicaCount: 2
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/runtime/gardener-operator/flux-kustomization.yaml")).To(ContainSubstring("path: landscape/components/runtime/gardener-operator/resources"))
+	})
+
+	It("should provide the high-availability feature", func() {
+		opts := components.NewOptions(test.BaseDir, nil, test.LandscapeDir, &configv1alpha1.LandscapeKitConfiguration{
+			Components: []configv1alpha1.ComponentConfig{{
+				Name:     gardeneroperator.ComponentName,
+				Features: []string{gardeneroperator.FeatureHighAvailability},
+			}},
+		}, resolveComponents("v1.128.3", operatorChart("v1.128.3")), fs, logr.Discard())
+		Expect(test.Generate(opts, gardeneroperator.NewComponent())).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/features/high-availability/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patches:
+- path: values-patch.yaml
+`))
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/features/high-availability/values-patch.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: gardener-operator
+  namespace: garden
+spec:
+  values:
+    replicaCount: 2
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/runtime/gardener-operator/resources/kustomization.yaml")).To(ContainSubstring(`components:
+  - ../../../../../base/components/runtime/gardener-operator/features/high-availability
+`))
+	})
+
+	It("should update the chart version when the root component version is bumped", func() {
+		Expect(generate(resolveComponents("v1.128.3", operatorChart("v1.128.3")), `{}`)).To(Succeed())
+
+		helmReleasePath := "/repo/base/components/runtime/gardener-operator/helmrelease.yaml"
+		content, err := fs.ReadFile(helmReleasePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.WriteFile(helmReleasePath, []byte(strings.Replace(string(content), "interval: 10m0s", "interval: 5m0s", 1)), 0600)).To(Succeed())
+
+		Expect(generate(resolveComponents("v1.129.0", operatorChart("v1.129.0")), `{}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/source.yaml")).To(ContainSubstring("tag: v1.129.0"))
+		Expect(fs.ReadFile(helmReleasePath)).To(ContainSubstring("interval: 5m0s"))
+	})
+
+	It("should use the original image references in the image vector overwrite if they were resolved", func() {
+		originalRefs = true
+		Expect(generate(resolveComponents("v1.128.3", operatorChart("v1.128.3"), operatorImage), `{}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/helmrelease.yaml")).To(ContainSubstring(`
+        repository: europe-docker.pkg.dev/gardener-project/releases/gardener/operator
+        tag: v1.128.3
+`))
+	})
+
+	It("should not set an image vector overwrite if the Gardener component does not contain images", func() {
+		Expect(generate(resolveComponents("v1.128.3", operatorChart("v1.128.3")), `{}`)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/runtime/gardener-operator/helmrelease.yaml")).NotTo(ContainSubstring("imageVectorOverwrite"))
+	})
+
+	It("should skip the component if the OCM components have not been resolved", func() {
+		Expect(generate(nil, `{}`)).To(Succeed())
+
+		Expect(fs.DirExists("/repo/base/components/runtime/gardener-operator")).To(BeFalse())
+		Expect(fs.DirExists("/repo/landscape/components/runtime/gardener-operator")).To(BeFalse())
+	})
+
+	It("should fail if the chart is not contained in the OCM components", func() {
+		Expect(generate(resolveComponents("v1.128.3"), `{}`)).To(MatchError(ContainSubstring(`failed to find the chart of component "gardener-operator": component github.com/gardener/gardener:v1.128.3 does not contain the helmChart/v1 resource operator`)))
+	})
+})
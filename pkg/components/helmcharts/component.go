@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helmcharts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/helm"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+// ComponentName is the name of the Helm charts component.
+const ComponentName = "helm-charts"
+
+// FluxKustomizationName returns the name of the Flux Kustomization managing the chart with the given name. It is also used as the name
+// of the directory of the chart, so that the charts do not collide with other components.
+func FluxKustomizationName(chartName string) string {
+	return "chart-" + chartName
+}
+
+// Config is the configuration of the Helm charts component.
+type Config struct {
+	// Charts are the Helm charts deployed in the landscape.
+	Charts []Chart `json:"charts"`
+}
+
+// Chart is a Helm chart deployed in the landscape.
+type Chart struct {
+	// Name is the name of the chart deployment. It is used for the Flux resources and as release name, the directories and the Flux
+	// Kustomization of the chart are prefixed with chart-, see FluxKustomizationName.
+	Name string `json:"name"`
+	// Repository is the URL of the chart repository. OCI repositories are prefixed with oci://, all others are treated as HTTP repositories.
+	Repository string `json:"repository"`
+	// Chart is the name of the chart in the repository. For OCI repositories, it is appended to the repository URL if set.
+	Chart string `json:"chart,omitempty"`
+	// Version is the version of the chart.
+	Version string `json:"version"`
+	// Namespace is the namespace the chart is installed into.
+	Namespace string `json:"namespace"`
+	// SecretRefName is the name of the secret in the flux-system namespace containing the credentials for the chart repository, e.g.
+	// the pull secret of a private OCI registry. If secrets are configured, it is scaffolded next to the chart, see Secrets.
+	SecretRefName string `json:"secretRefName,omitempty"`
+	// Layer is the rollout layer of the chart. Defaults to infrastructure.
+	Layer components.Layer `json:"layer,omitempty"`
+	// Values are the values of the chart in the landscape.
+	Values map[string]any `json:"values,omitempty"`
+}
+
+type component struct{}
+
+// NewComponent creates a new Helm charts component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
+	return nil
+}
+
+// Layer returns the rollout layer of the component. The charts can be assigned to later layers individually.
+func (c *component) Layer() components.Layer {
+	return components.LayerInfrastructure
+}
+
+// Namespaces returns the namespaces the configured charts are installed into.
+func (c *component) Namespaces(options components.Options) []components.Namespace {
+	config, err := decodeConfig(options)
+	if err != nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var namespaces []components.Namespace
+	for _, chart := range config.Charts {
+		if !slices.ContainsFunc(namespaces, func(n components.Namespace) bool { return n.Name == chart.Namespace }) {
+			namespaces = append(namespaces, components.Namespace{Name: chart.Namespace})
+		}
+	}
+	return namespaces
+}
+
+// FluxKustomizations returns the references to the Flux Kustomizations of the configured charts.
+// Components depending on the Helm charts component depend on all of them.
+func (c *component) FluxKustomizations(options components.Options) []kustomizev1.DependencyReference {
+	config, err := decodeConfig(options)
+	if err != nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var references []kustomizev1.DependencyReference
+	for _, chart := range config.Charts {
+		references = append(references, fluxKustomizationOptions(options, chart).DependencyReference())
+	}
+	return references
+}
+
+// Secrets returns the credentials of the chart repositories, which are applied together with the first chart referencing them.
+// OCI registries require a secret of type kubernetes.io/dockerconfigjson.
+func (c *component) Secrets(options components.Options) []components.Secret {
+	config, err := decodeConfig(options)
+	if err != nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var (
+		secrets []components.Secret
+		names   = sets.New[string]()
+	)
+	for _, chart := range config.Charts {
+		if chart.SecretRefName == "" || names.Has(chart.SecretRefName) {
+			continue
+		}
+		names.Insert(chart.SecretRefName)
+
+		secret := components.Secret{
+			Name:        chart.SecretRefName,
+			Namespace:   kustomization.FluxSystemNamespaceName,
+			Description: fmt.Sprintf("Credentials of the chart repository %s with the keys username and password.", chart.Repository),
+			Dir:         components.ComponentDir(chart.Layer, FluxKustomizationName(chart.Name)),
+		}
+		if strings.HasPrefix(chart.Repository, helm.OCIScheme) {
+			secret.Type = corev1.SecretTypeDockerConfigJson
+			secret.Description = fmt.Sprintf("Pull secret of the OCI registry %s with the key %s.", chart.Repository, corev1.DockerConfigJsonKey)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets
+}
+
+// GenerateBase generates the chart sources and HelmReleases of the configured charts into the base directory. They only contain the
+// generic settings of the charts, the landscape specific ones are set by the landscape overlays.
+func (c *component) GenerateBase(options components.Options) error {
+	config, err := decodeConfig(options)
+	if err != nil {
+		return err
+	}
+
+	for _, chart := range config.Charts {
+		objects, err := helm.NewChartSkeletonManifests(chartOptions(chart))
+		if err != nil {
+			return err
+		}
+		if err := components.WriteBaseManifests(options, components.ComponentDir(chart.Layer, FluxKustomizationName(chart.Name)), objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateLandscape generates a Flux managed overlay for each configured chart, which sets the repository, version, target namespace and
+// values of the chart.
+func (c *component) GenerateLandscape(options components.Options) error {
+	config, err := decodeConfig(options)
+	if err != nil {
+		return err
+	}
+
+	for _, chart := range config.Charts {
+		patches, err := helm.NewChartPatches(chartOptions(chart))
+		if err != nil {
+			return err
+		}
+
+		dir := components.ComponentDir(chart.Layer, FluxKustomizationName(chart.Name))
+		if err := components.WriteFluxComponent(options, dir, fluxKustomizationOptions(options, chart), dir, nil, patches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chartOptions returns the options of the chart source and the HelmRelease of the given chart in the flux-system namespace.
+func chartOptions(chart Chart) helm.ChartOptions {
+	return helm.ChartOptions{
+		Name:            chart.Name,
+		Namespace:       kustomization.FluxSystemNamespaceName,
+		Repository:      chart.Repository,
+		Chart:           chart.Chart,
+		Version:         chart.Version,
+		TargetNamespace: chart.Namespace,
+		SecretRefName:   chart.SecretRefName,
+		Values:          chart.Values,
+	}
+}
+
+// fluxKustomizationOptions returns the options of the Flux Kustomization managing the given chart.
+func fluxKustomizationOptions(options components.Options, chart Chart) kustomization.FluxKustomizationOptions {
+	return kustomization.FluxKustomizationOptions{
+		Name:      FluxKustomizationName(chart.Name),
+		SourceRef: flux.LandscapeSourceRef(options),
+		Wait:      true,
+	}
+}
+
+// decodeConfig decodes and validates the configuration of the component. The layer of the charts is defaulted.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+
+	names := sets.New[string]()
+	for i := range config.Charts {
+		chart := &config.Charts[i]
+		if chart.Layer == components.LayerNone {
+			chart.Layer = components.LayerInfrastructure
+		}
+
+		var missing []string
+		for field, value := range map[string]string{"name": chart.Name, "repository": chart.Repository, "version": chart.Version, "namespace": chart.Namespace} {
+			if value == "" {
+				missing = append(missing, field)
+			}
+		}
+		if !strings.HasPrefix(chart.Repository, helm.OCIScheme) && chart.Chart == "" {
+			missing = append(missing, "chart")
+		}
+		if len(missing) > 0 {
+			slices.Sort(missing)
+			return nil, fmt.Errorf("chart %d of component %q is missing %s", i, ComponentName, strings.Join(missing, ", "))
+		}
+
+		if errs := utilvalidation.IsDNS1123Label(chart.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("chart %d of component %q has an invalid name %q: %s", i, ComponentName, chart.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(chart.Name) {
+			return nil, fmt.Errorf("chart %q of component %q is configured more than once", chart.Name, ComponentName)
+		}
+		names.Insert(chart.Name)
+		if !slices.Contains(components.Layers, chart.Layer) {
+			return nil, fmt.Errorf("chart %q of component %q is assigned to unknown layer %q", chart.Name, ComponentName, chart.Layer)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helmcharts_test
+
+import (
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/helmcharts"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+var _ = Describe("Helm Charts Component", func() {
+	var fs afero.Afero
+
+	newOptions := func(config string) components.Options {
+		return components.NewOptions("/repo/base", nil, "/repo/landscape", &configv1alpha1.LandscapeKitConfiguration{
+			Components: []configv1alpha1.ComponentConfig{{Name: helmcharts.ComponentName, Config: &runtime.RawExtension{Raw: []byte(config)}}},
+		}, nil, fs, logr.Discard())
+	}
+
+	generate := func(opts components.Options) error {
+		registry := components.NewRegistry(1, false)
+		Expect(registry.RegisterComponent(flux.NewComponent())).To(Succeed())
+		Expect(registry.RegisterComponent(helmcharts.NewComponent())).To(Succeed())
+		return registry.Generate(opts)
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+	})
+
+	It("should generate the chart skeletons into the base and the chart settings into the landscape", func() {
+		opts := newOptions(`{"charts": [
+			{"name": "cert-manager", "repository": "oci://quay.io/jetstack/charts", "chart": "cert-manager", "version": "v1.16.0", "namespace": "cert-manager", "values": {"crds": {"enabled": true}}},
+			{"name": "my-operator", "repository": "https://charts.example.com", "chart": "operator", "version": "1.0.0", "namespace": "my-operator", "layer": "runtime"}
+		]}`)
+
+		Expect(generate(opts)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/infrastructure/chart-cert-manager/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- helmrelease.yaml
+- source.yaml
+`))
+		Expect(fs.ReadFile("/repo/base/components/infrastructure/chart-cert-manager/source.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  interval: 10m0s
+  layerSelector:
+    mediaType: application/vnd.cncf.helm.chart.content.v1.tar+gzip
+    operation: copy
+`))
+		Expect(fs.ReadFile("/repo/base/components/infrastructure/chart-cert-manager/helmrelease.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  chartRef:
+    kind: OCIRepository
+    name: cert-manager
+    namespace: flux-system
+  interval: 10m0s
+  releaseName: cert-manager
+`))
+		Expect(fs.ReadFile("/repo/base/components/runtime/chart-my-operator/helmrelease.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: my-operator
+  namespace: flux-system
+spec:
+  chart:
+    spec:
+      sourceRef:
+        kind: HelmRepository
+        name: my-operator
+        namespace: flux-system
+  interval: 10m0s
+  releaseName: my-operator
+`))
+
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../../../base/components/infrastructure/chart-cert-manager
+patches:
+- path: helmrelease-patch.yaml
+- path: source-patch.yaml
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/source-patch.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  ref:
+    tag: v1.16.0
+  url: oci://quay.io/jetstack/charts/cert-manager
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/helmrelease-patch.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  storageNamespace: cert-manager
+  targetNamespace: cert-manager
+  values:
+    crds:
+      enabled: true
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/runtime/chart-my-operator/resources/source-patch.yaml")).To(MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: my-operator
+  namespace: flux-system
+spec:
+  url: https://charts.example.com
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/runtime/chart-my-operator/resources/helmrelease-patch.yaml")).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: my-operator
+  namespace: flux-system
+spec:
+  chart:
+    spec:
+      chart: operator
+      version: 1.0.0
+  storageNamespace: my-operator
+  targetNamespace: my-operator
+  values: {}
+`))
+		content, err := fs.ReadFile("/repo/landscape/components/runtime/chart-my-operator/flux-kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("path: landscape/components/runtime/chart-my-operator/resources"))
+	})
+
+	It("should make dependents depend on the Flux Kustomizations of all charts", func() {
+		opts := newOptions(`{"charts": [
+			{"name": "cert-manager", "repository": "oci://quay.io/jetstack/charts", "chart": "cert-manager", "version": "v1.16.0", "namespace": "cert-manager"},
+			{"name": "external-dns", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "external-dns"}
+		]}`)
+		registry := components.NewRegistry(1, false)
+		Expect(registry.RegisterComponent(flux.NewComponent())).To(Succeed())
+		Expect(registry.RegisterComponent(helmcharts.NewComponent())).To(Succeed())
+		Expect(registry.RegisterComponent(&dependentComponent{})).To(Succeed())
+
+		Expect(registry.Generate(opts)).To(Succeed())
+
+		content, err := fs.ReadFile("/repo/landscape/components/runtime/dependent/flux-kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		k := &kustomizev1.Kustomization{}
+		Expect(yaml.Unmarshal(content, k)).To(Succeed())
+		Expect(k.Spec.DependsOn).To(Equal([]kustomizev1.DependencyReference{{Name: "chart-cert-manager", Namespace: "flux-system"}, {Name: "chart-external-dns", Namespace: "flux-system"}}))
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/flux-kustomization.yaml")).To(ContainSubstring("name: chart-cert-manager\n"))
+	})
+
+	It("should scaffold the credentials of the chart repositories if secrets are configured", func() {
+		opts := newOptions(`{"charts": [
+			{"name": "cert-manager", "repository": "oci://registry.example.com/charts", "chart": "cert-manager", "version": "v1.16.0", "namespace": "cert-manager", "secretRefName": "registry-credentials"},
+			{"name": "external-dns", "repository": "oci://registry.example.com/charts", "chart": "external-dns", "version": "1.15.0", "namespace": "external-dns", "secretRefName": "registry-credentials"},
+			{"name": "my-operator", "repository": "https://charts.example.com", "chart": "operator", "version": "1.0.0", "namespace": "my-operator", "secretRefName": "charts-credentials"}
+		]}`)
+		opts.GetConfig().Secrets = &configv1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}}
+
+		Expect(generate(opts)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/infrastructure/chart-cert-manager/source.yaml")).NotTo(ContainSubstring("secretRef"))
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/source-patch.yaml")).To(ContainSubstring(`  secretRef:
+    name: registry-credentials
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/secret-registry-credentials.sops.yaml")).To(Equal([]byte(`# Pull secret of the OCI registry oci://registry.example.com/charts with the key .dockerconfigjson.
+# Fill in the stringData and encrypt the file before committing it: sops --encrypt --in-place secret-registry-credentials.sops.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: registry-credentials
+  namespace: flux-system
+stringData: {}
+type: kubernetes.io/dockerconfigjson
+`)))
+		Expect(fs.Exists("/repo/landscape/components/infrastructure/chart-external-dns/resources/secret-registry-credentials.sops.yaml")).To(BeFalse())
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-my-operator/resources/secret-charts-credentials.sops.yaml")).To(ContainSubstring(`type: Opaque
+`))
+	})
+
+	It("should keep modified values when the chart is updated", func() {
+		opts := newOptions(`{"charts": [{"name": "cert-manager", "repository": "oci://quay.io/jetstack/charts", "chart": "cert-manager", "version": "v1.16.0", "namespace": "cert-manager"}]}`)
+		Expect(generate(opts)).To(Succeed())
+
+		valuesPath := "/repo/landscape/components/infrastructure/chart-cert-manager/resources/helmrelease-patch.yaml"
+		content, err := fs.ReadFile(valuesPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.WriteFile(valuesPath, []byte(strings.Replace(string(content), "values: {}", "values:\n    replicaCount: 2", 1)), 0600)).To(Succeed())
+
+		opts = newOptions(`{"charts": [{"name": "cert-manager", "repository": "oci://quay.io/jetstack/charts", "chart": "cert-manager", "version": "v1.17.0", "namespace": "cert-manager", "values": {"crds": {"enabled": true}}}]}`)
+		Expect(generate(opts)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/landscape/components/infrastructure/chart-cert-manager/resources/source-patch.yaml")).To(ContainSubstring("tag: v1.17.0"))
+		Expect(fs.ReadFile(valuesPath)).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  storageNamespace: cert-manager
+  targetNamespace: cert-manager
+  values:
+    crds:
+      enabled: true
+    replicaCount: 2
+`))
+	})
+
+	It("should declare the namespaces of the charts", func() {
+		opts := newOptions(`{"charts": [
+			{"name": "a", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "shared"},
+			{"name": "b", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "shared"}
+		]}`)
+
+		Expect(generate(opts)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/landscape/flux/shared-namespace.yaml")).To(ContainSubstring("name: shared"))
+	})
+
+	It("should fail for invalid chart configurations", func() {
+		Expect(generate(newOptions(`{"charts": [{"name": "a", "repository": "https://example.com"}]}`))).To(MatchError(ContainSubstring(`chart 0 of component "helm-charts" is missing chart, namespace, version`)))
+		Expect(generate(newOptions(`{"charts": [
+			{"name": "a", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "a"},
+			{"name": "a", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "a"}
+		]}`))).To(MatchError(ContainSubstring(`chart "a" of component "helm-charts" is configured more than once`)))
+		Expect(generate(newOptions(`{"charts": [{"name": "a", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "a", "layer": "unknown"}]}`))).To(MatchError(ContainSubstring(`chart "a" of component "helm-charts" is assigned to unknown layer "unknown"`)))
+		Expect(generate(newOptions(`{"charts": [{"name": "../a", "repository": "oci://example.com/charts", "version": "1.0.0", "namespace": "a"}]}`))).To(MatchError(ContainSubstring(`chart 0 of component "helm-charts" has an invalid name "../a"`)))
+	})
+})
+
+type dependentComponent struct{}
+
+func (c *dependentComponent) Name() string {
+	return "dependent"
+}
+
+func (c *dependentComponent) Dependencies() []string {
+	return []string{helmcharts.ComponentName}
+}
+
+func (c *dependentComponent) Layer() components.Layer {
+	return components.LayerRuntime
+}
+
+func (c *dependentComponent) GenerateBase(components.Options) error {
+	return nil
+}
+
+func (c *dependentComponent) GenerateLandscape(options components.Options) error {
+	return components.WriteFluxComponent(options, components.ComponentDir(c.Layer(), c.Name()), kustomization.FluxKustomizationOptions{
+		Name:      c.Name(),
+		SourceRef: flux.SourceRef,
+	}, "", nil, nil)
+}
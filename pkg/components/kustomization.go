@@ -19,35 +19,40 @@ import (
 
 // writeLandscapeComponentsKustomizations traverses through the generated components directory and adds
 // Kustomize kustomization.yaml files for each level until each component leaf node containing a Flux Kustomization is reached.
+// Layer directories also contain a Flux Kustomization, but are traversed as they are applied by it.
 func writeLandscapeComponentsKustomizations(options Options) error {
 	fs := options.GetFilesystem()
 	landscapeDir := options.GetLandscapeDir()
-	baseComponentsDir := filepath.Join(landscapeDir, DirName)
+	baseComponentsDir := path.Join(landscapeDir, DirName)
 
-	return fs.Walk(baseComponentsDir, writeKustomizationsToFileTree(fs, landscapeDir))
-}
+	if exists, err := fs.DirExists(baseComponentsDir); err != nil || !exists {
+		return err
+	}
 
-func writeKustomizationsToFileTree(fs afero.Afero, landscapeDir string) func(dir string, info os.FileInfo, err error) error {
-	var completedPaths []string
+	layerDirs := make(map[string]struct{}, len(Layers))
+	for _, layer := range Layers {
+		layerDirs[path.Join(landscapeDir, layer.Dir())] = struct{}{}
+	}
 
+	return fs.Walk(baseComponentsDir, writeKustomizationsToFileTree(fs, landscapeDir, layerDirs))
+}
+
+func writeKustomizationsToFileTree(fs afero.Afero, landscapeDir string, layerDirs map[string]struct{}) func(dir string, info os.FileInfo, err error) error {
 	return func(dir string, info os.FileInfo, err error) error {
-		if !info.IsDir() || err != nil {
+		if err != nil || !info.IsDir() {
 			return err
 		}
-
-		for _, p := range completedPaths {
-			if isCompleted, err := path.Match(p, dir); err != nil || isCompleted {
-				return err
-			}
-		}
+		// Generated paths must not depend on the platform.
+		dir = filepath.ToSlash(dir)
 
 		exists, err := fs.Exists(path.Join(dir, kustomization.FluxKustomizationFileName))
 		if err != nil {
 			return err
 		}
-		if exists {
-			completedPaths = append(completedPaths, dir+"/*")
-			return nil
+		// Layer directories are managed by their own Flux Kustomization, but the Flux Kustomization applies the layer directory itself.
+		if _, isLayerDir := layerDirs[path.Clean(dir)]; exists && !isLayerDir {
+			// The directory is a component leaf node managed by its own Flux Kustomization, there is no need to traverse it.
+			return filepath.SkipDir
 		}
 
 		subDirs, err := fs.ReadDir(dir)
@@ -63,7 +68,6 @@ func writeKustomizationsToFileTree(fs afero.Afero, landscapeDir string) func(dir
 				}
 				if exists {
 					directories = append(directories, path.Join(subDir.Name(), kustomization.FluxKustomizationFileName))
-					completedPaths = append(completedPaths, path.Join(dir, subDir.Name(), "*"))
 				} else {
 					directories = append(directories, subDir.Name())
 				}
@@ -81,7 +85,7 @@ func writeKustomizationFile(fs afero.Afero, landscapeDir, relativePath string, d
 		objects = make(map[string][]byte)
 	)
 
-	objects[kustomization.KustomizationFileName], err = yaml.Marshal(kustomization.NewKustomization(directories, nil))
+	objects[kustomization.KustomizationFileName], err = yaml.Marshal(kustomization.NewKustomization(directories, nil, nil))
 	if err != nil {
 		return err
 	}
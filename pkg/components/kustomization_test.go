@@ -19,7 +19,7 @@ var _ = Describe("Component Generation", func() {
 
 	BeforeEach(func() {
 		fs = afero.Afero{Fs: afero.NewMemMapFs()}
-		opts = NewOptions("/baseDir", "/landscapeDir", fs, logr.Discard())
+		opts = NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, fs, logr.Discard())
 	})
 
 	It("should generate kustomization files within a component directory", func() {
@@ -43,6 +43,25 @@ var _ = Describe("Component Generation", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(string(content)).To(Equal("apiVersion: dummy"))
 	})
+
+	It("should traverse layer directories containing a Flux Kustomization", func() {
+		runtimeDir := opts.GetLandscapeDir() + "/components/runtime"
+		Expect(fs.MkdirAll(runtimeDir+"/operator/resources", 0700)).To(Succeed())
+		Expect(fs.WriteFile(runtimeDir+"/flux-kustomization.yaml", []byte(`apiVersion: kustomize.toolkit.fluxcd.io/v1`), 0600)).To(Succeed())
+		Expect(fs.WriteFile(runtimeDir+"/operator/flux-kustomization.yaml", []byte(`apiVersion: kustomize.toolkit.fluxcd.io/v1`), 0600)).To(Succeed())
+
+		Expect(writeLandscapeComponentsKustomizations(opts)).To(Succeed())
+
+		content, err := fs.ReadFile(opts.GetLandscapeDir() + "/components/kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("- runtime/flux-kustomization.yaml\n"))
+
+		content, err = fs.ReadFile(runtimeDir + "/kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("- operator/flux-kustomization.yaml\n"))
+
+		Expect(fs.Exists(runtimeDir + "/operator/kustomization.yaml")).To(BeFalse())
+	})
 })
 
 func generateExampleComponentsDirectory(fs afero.Afero, opts Options) {
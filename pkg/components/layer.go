@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"path"
+	"slices"
+)
+
+// Layer is a rollout layer of the components. Each layer is managed by its own Flux Kustomization, which depends on the previous layer.
+type Layer string
+
+const (
+	// LayerNone is used by components that are not rolled out via the components tree, e.g. Flux itself.
+	LayerNone Layer = ""
+	// LayerInfrastructure contains infrastructure components of the runtime cluster, e.g. certificate management or DNS.
+	LayerInfrastructure Layer = "infrastructure"
+	// LayerRuntime contains the components of the runtime cluster, e.g. the gardener-operator.
+	LayerRuntime Layer = "runtime"
+	// LayerVirtualGarden contains the components of the virtual garden, e.g. the Garden resource.
+	LayerVirtualGarden Layer = "virtual-garden"
+	// LayerExtensions contains the Gardener extensions.
+	LayerExtensions Layer = "extensions"
+	// LayerSeeds contains the seeds of the landscape, which require the extensions to be available.
+	LayerSeeds Layer = "seeds"
+)
+
+// Layers contains all layers in their rollout order.
+var Layers = []Layer{
+	LayerInfrastructure,
+	LayerRuntime,
+	LayerVirtualGarden,
+	LayerExtensions,
+	LayerSeeds,
+}
+
+// Dir returns the directory of the layer within the landscape directory.
+func (l Layer) Dir() string {
+	return path.Join(DirName, string(l))
+}
+
+// FluxKustomizationName returns the name of the Flux Kustomization managing the layer.
+func (l Layer) FluxKustomizationName() string {
+	return "glk-" + string(l)
+}
+
+// ComponentDir returns the directory of the component with the given name and layer within the landscape directory.
+func ComponentDir(layer Layer, name string) string {
+	return path.Join(layer.Dir(), name)
+}
+
+// layerIndex returns the position of the given layer in the rollout order, or -1 if it is no known layer.
+func layerIndex(layer Layer) int {
+	return slices.Index(Layers, layer)
+}
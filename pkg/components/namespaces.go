@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodSecurityLevel is a Pod Security Admission level enforced in a namespace.
+type PodSecurityLevel string
+
+const (
+	// PodSecurityLevelPrivileged allows all pods.
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	// PodSecurityLevelBaseline prevents known privilege escalations.
+	PodSecurityLevelBaseline PodSecurityLevel = "baseline"
+	// PodSecurityLevelRestricted enforces the current pod hardening best practices.
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+
+	// PodSecurityEnforceLabel is the label of a namespace defining the enforced Pod Security Admission level.
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+// Namespace is a namespace required by a component.
+type Namespace struct {
+	// Name is the name of the namespace.
+	Name string
+	// PodSecurityLevel is the enforced Pod Security Admission level. If empty, no level is enforced explicitly.
+	PodSecurityLevel PodSecurityLevel
+	// Labels are additional labels of the namespace.
+	Labels map[string]string
+	// Annotations are additional annotations of the namespace.
+	Annotations map[string]string
+}
+
+// Object returns the namespace object.
+func (n Namespace) Object() *corev1.Namespace {
+	labels := maps.Clone(n.Labels)
+	if n.PodSecurityLevel != "" {
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels[PodSecurityEnforceLabel] = string(n.PodSecurityLevel)
+	}
+
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        n.Name,
+			Labels:      labels,
+			Annotations: maps.Clone(n.Annotations),
+		},
+	}
+}
+
+// NamespaceDeclarer is implemented by components that require namespaces.
+// The declared namespaces of all components are generated once at the beginning of the rollout, see Options.GetNamespaces.
+type NamespaceDeclarer interface {
+	// Namespaces returns the namespaces required by the component.
+	Namespaces(Options) []Namespace
+}
+
+// collectNamespaces returns the namespaces declared by the given components merged with the namespaces of the configuration, sorted by
+// name. Components may declare the same namespace, but it is an error if they declare different Pod Security levels or label and
+// annotation values for it. The configuration takes precedence over the declarations of the components.
+func collectNamespaces(opts Options, components []Interface) ([]Namespace, error) {
+	var (
+		namespaces = make(map[string]*Namespace)
+		declarers  = make(map[string]string)
+	)
+	for _, component := range components {
+		declarer, ok := component.(NamespaceDeclarer)
+		if !ok {
+			continue
+		}
+		for _, declared := range declarer.Namespaces(newComponentOptions(opts, component)) {
+			existing, ok := namespaces[declared.Name]
+			if !ok {
+				namespaces[declared.Name] = &Namespace{
+					Name:             declared.Name,
+					PodSecurityLevel: declared.PodSecurityLevel,
+					Labels:           maps.Clone(declared.Labels),
+					Annotations:      maps.Clone(declared.Annotations),
+				}
+				declarers[declared.Name] = component.Name()
+				continue
+			}
+
+			conflictf := func(format string, args ...any) error {
+				return fmt.Errorf("namespace %q is declared with conflicting %s by components %q and %q",
+					declared.Name, fmt.Sprintf(format, args...), declarers[declared.Name], component.Name())
+			}
+			if declared.PodSecurityLevel != "" {
+				if existing.PodSecurityLevel != "" && existing.PodSecurityLevel != declared.PodSecurityLevel {
+					return nil, conflictf("Pod Security levels %q and %q", existing.PodSecurityLevel, declared.PodSecurityLevel)
+				}
+				existing.PodSecurityLevel = declared.PodSecurityLevel
+			}
+			var err error
+			if existing.Labels, err = mergeMetadata(existing.Labels, declared.Labels); err != nil {
+				return nil, conflictf("label %s", err)
+			}
+			if existing.Annotations, err = mergeMetadata(existing.Annotations, declared.Annotations); err != nil {
+				return nil, conflictf("annotation %s", err)
+			}
+		}
+	}
+
+	for _, conf := range opts.GetConfig().Namespaces {
+		namespace, ok := namespaces[conf.Name]
+		if !ok {
+			namespace = &Namespace{Name: conf.Name}
+			namespaces[conf.Name] = namespace
+		}
+		if conf.PodSecurityLevel != "" {
+			namespace.PodSecurityLevel = PodSecurityLevel(conf.PodSecurityLevel)
+		}
+		if len(conf.Labels) > 0 {
+			namespace.Labels = mergeWithPrecedence(namespace.Labels, conf.Labels)
+		}
+		if len(conf.Annotations) > 0 {
+			namespace.Annotations = mergeWithPrecedence(namespace.Annotations, conf.Annotations)
+		}
+	}
+
+	result := make([]Namespace, 0, len(namespaces))
+	for _, name := range slices.Sorted(maps.Keys(namespaces)) {
+		result = append(result, *namespaces[name])
+	}
+	return result, nil
+}
+
+// mergeMetadata merges the given labels or annotations. It returns an error if a key has different values.
+func mergeMetadata(existing, declared map[string]string) (map[string]string, error) {
+	var conflicts []string
+	for _, key := range slices.Sorted(maps.Keys(declared)) {
+		if value, ok := existing[key]; ok && value != declared[key] {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%q and %q)", key, value, declared[key]))
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(conflicts, ", "))
+	}
+	return mergeWithPrecedence(existing, declared), nil
+}
+
+func mergeWithPrecedence(existing, overrides map[string]string) map[string]string {
+	if existing == nil && overrides == nil {
+		return nil
+	}
+	merged := maps.Clone(existing)
+	if merged == nil {
+		merged = make(map[string]string, len(overrides))
+	}
+	maps.Copy(merged, overrides)
+	return merged
+}
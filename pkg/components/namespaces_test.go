@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+)
+
+var _ = Describe("Namespaces", func() {
+	var (
+		config *configv1alpha1.LandscapeKitConfiguration
+		opts   Options
+	)
+
+	BeforeEach(func() {
+		config = &configv1alpha1.LandscapeKitConfiguration{}
+		opts = NewOptions("/base", nil, "/landscape", config, nil, afero.Afero{Fs: afero.NewMemMapFs()}, logr.Discard())
+	})
+
+	Describe("#collectNamespaces", func() {
+		It("should merge the declarations of all components", func() {
+			Expect(collectNamespaces(opts, []Interface{
+				&namespaceComponent{name: "a", namespaces: []Namespace{
+					{Name: "garden", PodSecurityLevel: PodSecurityLevelPrivileged, Labels: map[string]string{"a": "true"}},
+					{Name: "cert-management"},
+				}},
+				&fakeComponent{name: "b"},
+				&namespaceComponent{name: "c", namespaces: []Namespace{
+					{Name: "garden", Labels: map[string]string{"a": "true", "c": "true"}, Annotations: map[string]string{"owner": "c"}},
+				}},
+			})).To(Equal([]Namespace{
+				{Name: "cert-management"},
+				{Name: "garden", PodSecurityLevel: PodSecurityLevelPrivileged, Labels: map[string]string{"a": "true", "c": "true"}, Annotations: map[string]string{"owner": "c"}},
+			}))
+		})
+
+		It("should apply the configuration with precedence", func() {
+			config.Namespaces = []configv1alpha1.NamespaceConfig{
+				{Name: "garden", PodSecurityLevel: "baseline", Labels: map[string]string{"a": "false"}},
+				{Name: "monitoring", Annotations: map[string]string{"owner": "ops"}},
+			}
+
+			Expect(collectNamespaces(opts, []Interface{
+				&namespaceComponent{name: "a", namespaces: []Namespace{
+					{Name: "garden", PodSecurityLevel: PodSecurityLevelPrivileged, Labels: map[string]string{"a": "true"}},
+				}},
+			})).To(Equal([]Namespace{
+				{Name: "garden", PodSecurityLevel: PodSecurityLevelBaseline, Labels: map[string]string{"a": "false"}},
+				{Name: "monitoring", Annotations: map[string]string{"owner": "ops"}},
+			}))
+		})
+
+		It("should fail for conflicting Pod Security levels", func() {
+			_, err := collectNamespaces(opts, []Interface{
+				&namespaceComponent{name: "a", namespaces: []Namespace{{Name: "garden", PodSecurityLevel: PodSecurityLevelPrivileged}}},
+				&namespaceComponent{name: "b", namespaces: []Namespace{{Name: "garden", PodSecurityLevel: PodSecurityLevelRestricted}}},
+			})
+			Expect(err).To(MatchError(`namespace "garden" is declared with conflicting Pod Security levels "privileged" and "restricted" by components "a" and "b"`))
+		})
+
+		It("should fail for conflicting labels", func() {
+			_, err := collectNamespaces(opts, []Interface{
+				&namespaceComponent{name: "a", namespaces: []Namespace{{Name: "garden", Labels: map[string]string{"role": "a"}}}},
+				&namespaceComponent{name: "b", namespaces: []Namespace{{Name: "garden", Labels: map[string]string{"role": "b"}}}},
+			})
+			Expect(err).To(MatchError(`namespace "garden" is declared with conflicting label role ("a" and "b") by components "a" and "b"`))
+		})
+	})
+
+	Describe("Namespace#Object", func() {
+		It("should add the Pod Security label", func() {
+			namespace := Namespace{Name: "garden", PodSecurityLevel: PodSecurityLevelBaseline, Labels: map[string]string{"foo": "bar"}}
+
+			Expect(namespace.Object().Labels).To(Equal(map[string]string{"foo": "bar", PodSecurityEnforceLabel: "baseline"}))
+			Expect(namespace.Labels).To(HaveLen(1))
+		})
+	})
+})
+
+type namespaceComponent struct {
+	fakeComponent
+
+	name       string
+	namespaces []Namespace
+}
+
+func (c *namespaceComponent) Name() string {
+	return c.name
+}
+
+func (c *namespaceComponent) Namespaces(Options) []Namespace {
+	return c.namespaces
+}
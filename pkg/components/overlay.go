@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	kustomize "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+// WriteBaseManifests writes the given manifests into the given directory of the base directory.
+// The written kustomization.yaml references all manifests, so that landscapes can consume the directory as a whole.
+// The manifests are written according to the configured file layout.
+func WriteBaseManifests(opts Options, dir string, objects map[string][]byte) error {
+	objects, err := applyFileLayout(opts, opts.GetBaseDir(), dir, objects)
+	if err != nil {
+		return err
+	}
+	return kustomization.WriteKustomizationComponent(objects, opts.GetBaseDir(), dir, opts.GetFilesystem())
+}
+
+// applyFileLayout applies the configured file layout to the given manifests of the given directory, see files.ApplyFileLayout.
+func applyFileLayout(opts Options, rootDir, dir string, objects map[string][]byte) (map[string][]byte, error) {
+	return files.ApplyFileLayout(objects, rootDir, dir, files.FileLayout(opts.GetConfig().FileLayout), opts.GetFilesystem())
+}
+
+const (
+	// FeaturesDirName is the name of the directory within a base directory of a component that contains its optional features.
+	FeaturesDirName = "features"
+
+	// BaseSourceDirName is the directory in the landscape repository where Flux includes the base source, if configured.
+	BaseSourceDirName = "glk-base"
+)
+
+// LandscapeRepositoryPath returns the path of the landscape directory within the Git repository synced by Flux.
+// If a base source is configured, the landscape directory is the root of the repository.
+func LandscapeRepositoryPath(opts Options) string {
+	if opts.GetConfig().BaseSource != nil {
+		return ""
+	}
+	return files.ComputeBasePath(opts.GetLandscapeDir(), opts.GetBaseDir())
+}
+
+// referencedBaseDir returns the base directory as it is referenced by the overlays. If a base source is configured, it is the
+// directory the base source is included into by Flux, otherwise it is the base directory itself.
+func referencedBaseDir(opts Options) string {
+	if opts.GetConfig().BaseSource != nil {
+		return path.Join(opts.GetLandscapeDir(), BaseSourceDirName)
+	}
+	return opts.GetBaseDir()
+}
+
+// WriteBaseFeature writes an optional feature of a component as kustomize Component into the features directory of the given
+// directory of the base directory. Landscapes enable the feature by listing it in the features of their component configuration.
+func WriteBaseFeature(opts Options, dir, feature string, objects, patches map[string][]byte) error {
+	featureDir := path.Join(dir, FeaturesDirName, feature)
+	objects, err := applyFileLayout(opts, opts.GetBaseDir(), featureDir, objects)
+	if err != nil {
+		return err
+	}
+
+	var kustomizePatches []kustomize.Patch
+	for _, fileName := range slices.Sorted(maps.Keys(patches)) {
+		kustomizePatches = append(kustomizePatches, kustomize.Patch{Path: fileName})
+	}
+
+	content, err := yaml.Marshal(kustomization.NewComponent(slices.Sorted(maps.Keys(objects)), kustomizePatches))
+	if err != nil {
+		return err
+	}
+
+	allObjects := map[string][]byte{kustomization.KustomizationFileName: content}
+	maps.Copy(allObjects, objects)
+	maps.Copy(allObjects, patches)
+	return files.WriteObjectsToFilesystem(allObjects, opts.GetBaseDir(), featureDir, opts.GetFilesystem())
+}
+
+// WriteLandscapeOverlay writes a kustomization.yaml into the given directory of the landscape directory, which references the given
+// directory of the base directory by its relative path. Landscape-only manifests are written next to it and added as further resources,
+// landscape-only patches are added as patches. If baseDir is empty, no base is referenced.
+// If the landscape belongs to groups, the landscape references the most specific group instead, see writeGroupOverlays.
+// The features enabled in the component configuration are added as kustomize components, see WriteBaseFeature.
+func WriteLandscapeOverlay(opts Options, dir, baseDir string, objects, patches map[string][]byte) error {
+	return writeLandscapeOverlay(opts, dir, baseDir, objects, patches, nil)
+}
+
+// writeLandscapeOverlay writes the landscape overlay like WriteLandscapeOverlay. The given files, which have already been written into the
+// directory, are added as further resources.
+func writeLandscapeOverlay(opts Options, dir, baseDir string, objects, patches map[string][]byte, writtenFiles []string) error {
+	var (
+		resources  []string
+		components []string
+	)
+	if baseDir != "" {
+		upperDir, err := writeGroupOverlays(opts, baseDir)
+		if err != nil {
+			return err
+		}
+		relativeUpperPath, err := files.ComputeRelativeBasePath(upperDir, opts.GetLandscapeDir(), dir)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, path.Join(relativeUpperPath, baseDir))
+
+		relativeBasePath, err := files.ComputeRelativeBasePath(referencedBaseDir(opts), opts.GetLandscapeDir(), dir)
+		if err != nil {
+			return err
+		}
+		if components, err = enabledFeatures(opts, baseDir, relativeBasePath); err != nil {
+			return err
+		}
+	}
+
+	objects, err := applyFileLayout(opts, opts.GetLandscapeDir(), dir, objects)
+	if err != nil {
+		return err
+	}
+	resources = append(resources, slices.Sorted(maps.Keys(objects))...)
+	resources = append(resources, writtenFiles...)
+
+	var kustomizePatches []kustomize.Patch
+	for _, fileName := range slices.Sorted(maps.Keys(patches)) {
+		kustomizePatches = append(kustomizePatches, kustomize.Patch{Path: fileName})
+	}
+
+	content, err := yaml.Marshal(kustomization.NewKustomization(resources, kustomizePatches, components))
+	if err != nil {
+		return err
+	}
+
+	allObjects := map[string][]byte{kustomization.KustomizationFileName: content}
+	maps.Copy(allObjects, objects)
+	maps.Copy(allObjects, patches)
+	return files.WriteObjectsToFilesystem(allObjects, opts.GetLandscapeDir(), dir, opts.GetFilesystem())
+}
+
+// writeGroupOverlays writes a kustomization.yaml into the given directory of the base directory in each group directory, which references
+// the same directory in the base or the previous group directory. Group-wide modifications are merged like in any other generated file.
+// It returns the most specific directory the landscape overlay has to reference.
+func writeGroupOverlays(opts Options, baseDir string) (string, error) {
+	upperDir := referencedBaseDir(opts)
+	for _, groupDir := range opts.GetGroupDirs() {
+		relativeUpperPath, err := files.ComputeRelativeBasePath(upperDir, groupDir, baseDir)
+		if err != nil {
+			return "", err
+		}
+		content, err := yaml.Marshal(kustomization.NewKustomization([]string{path.Join(relativeUpperPath, baseDir)}, nil, nil))
+		if err != nil {
+			return "", err
+		}
+		if err := files.WriteObjectsToFilesystem(map[string][]byte{kustomization.KustomizationFileName: content}, groupDir, baseDir, opts.GetFilesystem()); err != nil {
+			return "", err
+		}
+		upperDir = groupDir
+	}
+	return upperDir, nil
+}
+
+// enabledFeatures returns the paths of the features enabled in the component configuration relative to the landscape overlay.
+// It returns an error if an enabled feature is not available in the given directory of the base directory.
+func enabledFeatures(opts Options, baseDir, relativeBasePath string) ([]string, error) {
+	componentConfig := opts.GetComponentConfig()
+	if componentConfig == nil {
+		return nil, nil
+	}
+
+	var features []string
+	for _, feature := range componentConfig.Features {
+		featureDir := path.Join(baseDir, FeaturesDirName, feature)
+		exists, err := opts.GetFilesystem().DirExists(path.Join(opts.GetBaseDir(), featureDir))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("feature %q of component %q is not available in %s", feature, componentConfig.Name, path.Join(opts.GetBaseDir(), baseDir, FeaturesDirName))
+		}
+		features = append(features, path.Join(relativeBasePath, featureDir))
+	}
+	return features, nil
+}
+
+// WriteFluxComponent writes a component leaf into the given directory of the landscape directory, which is managed by its own Flux
+// Kustomization. The Flux Kustomization is written to flux-kustomization.yaml and applies the resources directory next to it.
+// The resources directory is written as an overlay of the given directory of the base directory, see WriteLandscapeOverlay.
+// The Flux dependencies of the generated component are added to the dependencies given in the Flux Kustomization options.
+// The secrets of the generated component applied from the directory are scaffolded into the resources directory, and the Flux
+// Kustomization decrypts them, see SecretDeclarer.
+func WriteFluxComponent(opts Options, dir string, fluxOpts kustomization.FluxKustomizationOptions, baseDir string, objects, patches map[string][]byte) error {
+	resourcesDir := path.Join(dir, kustomization.OverrideDir)
+	secretFiles, err := writeSecrets(opts, dir, resourcesDir)
+	if err != nil {
+		return err
+	}
+	if len(secretFiles) > 0 {
+		fluxOpts.DecryptionSecretName = DecryptionSecretName
+	}
+
+	fluxOpts.DependsOn = append(slices.Clone(fluxOpts.DependsOn), opts.GetFluxDependsOn()...)
+	fluxKustomization := kustomization.NewFluxKustomization(fluxOpts, path.Join(LandscapeRepositoryPath(opts), resourcesDir))
+	content, err := yaml.Marshal(fluxKustomization)
+	if err != nil {
+		return err
+	}
+
+	if err := files.WriteObjectsToFilesystem(map[string][]byte{kustomization.FluxKustomizationFileName: content}, opts.GetLandscapeDir(), dir, opts.GetFilesystem()); err != nil {
+		return err
+	}
+	return writeLandscapeOverlay(opts, resourcesDir, baseDir, objects, patches, secretFiles)
+}
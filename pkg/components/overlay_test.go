@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+var _ = Describe("Overlay", func() {
+	var (
+		fs   afero.Afero
+		opts Options
+	)
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		opts = &componentOptions{
+			Options:   NewOptions("/repo/base", nil, "/repo/landscape", nil, nil, fs, logr.Discard()),
+			dependsOn: []kustomizev1.DependencyReference{{Name: "dependency"}},
+		}
+	})
+
+	Describe("#WriteLandscapeOverlay", func() {
+		It("should reference the base and add landscape manifests and patches", func() {
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", map[string][]byte{
+				"secret.yaml": []byte("kind: Secret\n"),
+			}, map[string][]byte{
+				"patch.yaml": []byte("kind: Deployment\n"),
+			})).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/landscape/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../base/components/foo
+- secret.yaml
+patches:
+- path: patch.yaml
+`))
+			Expect(fs.Exists("/repo/landscape/components/foo/secret.yaml")).To(BeTrue())
+			Expect(fs.Exists("/repo/landscape/components/foo/patch.yaml")).To(BeTrue())
+		})
+	})
+
+	Describe("#WriteLandscapeOverlay with split file layout", func() {
+		It("should write each object of multi-document manifests into its own file", func() {
+			opts = NewOptions("/repo/base", nil, "/repo/landscape", &configv1alpha1.LandscapeKitConfiguration{FileLayout: configv1alpha1.FileLayoutSplit}, nil, fs, logr.Discard())
+
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "", map[string][]byte{
+				"manifests.yaml": []byte("kind: Secret\nmetadata:\n  name: foo\n---\nkind: ConfigMap\nmetadata:\n  name: bar\n"),
+			}, nil)).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/landscape/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- configmap-bar.yaml
+- secret-foo.yaml
+`))
+			Expect(fs.Exists("/repo/landscape/components/foo/manifests.yaml")).To(BeFalse())
+		})
+	})
+
+	Describe("#WriteLandscapeOverlay with base source", func() {
+		It("should reference the base included into the landscape repository", func() {
+			opts = NewOptions("/repo/base", nil, "/landscape", &configv1alpha1.LandscapeKitConfiguration{
+				BaseSource: &configv1alpha1.BaseSource{URL: "https://example.com/base", Ref: configv1alpha1.BaseSourceRef{Tag: "v1.0.0"}},
+			}, nil, fs, logr.Discard())
+
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscape/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../glk-base/components/foo
+`))
+			Expect(LandscapeRepositoryPath(opts)).To(BeEmpty())
+		})
+	})
+
+	Describe("#WriteLandscapeOverlay with groups", func() {
+		BeforeEach(func() {
+			opts = NewOptions("/repo/base", []string{"/repo/groups/eu", "/repo/groups/eu-west"}, "/repo/landscape", nil, nil, fs, logr.Discard())
+		})
+
+		It("should chain the overlays from the base over the groups to the landscape", func() {
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/groups/eu/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../../base/components/foo
+`))
+			Expect(fs.ReadFile("/repo/groups/eu-west/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../eu/components/foo
+`))
+			Expect(fs.ReadFile("/repo/landscape/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../groups/eu-west/components/foo
+`))
+			Expect(fs.Exists("/repo/groups/eu/.glk/defaults/components/foo/kustomization.yaml")).To(BeTrue())
+			Expect(fs.Exists("/repo/groups/eu-west/.glk/defaults/components/foo/kustomization.yaml")).To(BeTrue())
+		})
+
+		It("should keep group-wide modifications", func() {
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(Succeed())
+			Expect(fs.WriteFile("/repo/groups/eu/components/foo/kustomization.yaml", []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../../base/components/foo
+patches:
+- path: mirror.yaml
+`), 0600)).To(Succeed())
+
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(Succeed())
+
+			content, err := fs.ReadFile("/repo/groups/eu/components/foo/kustomization.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("path: mirror.yaml"))
+		})
+	})
+
+	Describe("#WriteBaseFeature", func() {
+		It("should write the feature as kustomize component", func() {
+			Expect(WriteBaseFeature(opts, "components/foo", "ha", map[string][]byte{
+				"pdb.yaml": []byte("kind: PodDisruptionBudget\n"),
+			}, map[string][]byte{
+				"replicas.yaml": []byte("kind: Deployment\n"),
+			})).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/base/components/foo/features/ha/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+resources:
+- pdb.yaml
+patches:
+- path: replicas.yaml
+`))
+			Expect(fs.Exists("/repo/base/components/foo/features/ha/pdb.yaml")).To(BeTrue())
+			Expect(fs.Exists("/repo/base/components/foo/features/ha/replicas.yaml")).To(BeTrue())
+		})
+	})
+
+	Describe("#WriteLandscapeOverlay with features", func() {
+		BeforeEach(func() {
+			opts.(*componentOptions).componentConfig = &configv1alpha1.ComponentConfig{Name: "foo", Features: []string{"ha", "debug"}}
+		})
+
+		It("should add the enabled features as kustomize components", func() {
+			Expect(WriteBaseFeature(opts, "components/foo", "ha", map[string][]byte{"pdb.yaml": []byte("kind: PodDisruptionBudget\n")}, nil)).To(Succeed())
+			Expect(WriteBaseFeature(opts, "components/foo", "debug", nil, map[string][]byte{"debug.yaml": []byte("kind: Deployment\n")})).To(Succeed())
+			Expect(WriteBaseFeature(opts, "components/foo", "unused", nil, nil)).To(Succeed())
+
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/landscape/components/foo/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../../base/components/foo
+components:
+- ../../../base/components/foo/features/ha
+- ../../../base/components/foo/features/debug
+`))
+		})
+
+		It("should fail for features that are not available in the base", func() {
+			Expect(WriteBaseFeature(opts, "components/foo", "ha", nil, nil)).To(Succeed())
+
+			Expect(WriteLandscapeOverlay(opts, "components/foo", "components/foo", nil, nil)).To(MatchError(
+				`feature "debug" of component "foo" is not available in /repo/base/components/foo/features`))
+		})
+	})
+
+	Describe("#WriteFluxComponent", func() {
+		It("should write the Flux Kustomization and the resources directory", func() {
+			Expect(WriteFluxComponent(opts, "components/foo", kustomization.FluxKustomizationOptions{
+				Name:      "foo",
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{Kind: "GitRepository", Name: "flux-system", Namespace: "flux-system"},
+				Prune:     true,
+				DependsOn: []kustomizev1.DependencyReference{{Name: "other"}},
+			}, "", map[string][]byte{"configmap.yaml": []byte("kind: ConfigMap\n")}, nil)).To(Succeed())
+
+			Expect(fs.ReadFile("/repo/landscape/components/foo/flux-kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: foo
+  namespace: flux-system
+spec:
+  dependsOn:
+  - name: other
+  - name: dependency
+  interval: 10m0s
+  path: landscape/components/foo/resources
+  postBuild:
+    substituteFrom:
+    - kind: ConfigMap
+      name: glk-variables
+      optional: true
+  prune: true
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+    namespace: flux-system
+status: {}
+`))
+			Expect(fs.ReadFile("/repo/landscape/components/foo/resources/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- configmap.yaml
+`))
+			Expect(fs.Exists("/repo/landscape/components/foo/resources/configmap.yaml")).To(BeTrue())
+		})
+	})
+})
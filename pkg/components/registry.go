@@ -4,52 +4,412 @@
 
 package components
 
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
 // Registry is the interface for a component registry.
 type Registry interface {
 	// RegisterComponent registers a component in the registry.
-	RegisterComponent(component Interface)
+	// It returns an error if a component with the same name has already been registered or if its layer is unknown.
+	RegisterComponent(component Interface) error
 	// Generate generates all registered components.
 	Generate(opts Options) error
 }
 
 type registry struct {
 	components []Interface
+	workers    int
+	prune      bool
 }
 
 // RegisterComponent registers a component in the registry.
-func (r *registry) RegisterComponent(component Interface) {
+// It returns an error if a component with the same name has already been registered or if its layer is unknown.
+func (r *registry) RegisterComponent(component Interface) error {
+	if component.Name() == "" {
+		return fmt.Errorf("component name must not be empty")
+	}
+	if slices.ContainsFunc(r.components, func(c Interface) bool { return c.Name() == component.Name() }) {
+		return fmt.Errorf("component %q is already registered", component.Name())
+	}
+	if component.Layer() != LayerNone && layerIndex(component.Layer()) == -1 {
+		return fmt.Errorf("component %q is assigned to unknown layer %q", component.Name(), component.Layer())
+	}
 	r.components = append(r.components, component)
+	return nil
 }
 
-// Generate generates all registered components. Generation happens serially in the order of registration.
+// Generate generates all registered components.
+// The base directory is always generated first, the landscape directory afterwards if it is set.
+// The namespaces declared by the components are collected beforehand, conflicting declarations are rejected.
+// Components are generated concurrently by a bounded number of workers. A component is only generated after all its dependencies
+// have been generated successfully. Errors of all failed components are aggregated in the topological order of the components.
+// If secrets are configured, the SOPS configuration encrypting the secrets declared by the components is written into the landscape directory.
+// Afterwards, previously generated files that are no longer produced are reported, or pruned in the landscape directory if configured.
+// Finally, variables used in the landscape but not defined in the configuration are reported.
+// The checksums of all generated files are recorded, so that files and defaults changed in the meantime are detected by the next run.
 func (r *registry) Generate(opts Options) error {
-	if opts.GetLandscapeDir() == "" {
-		return r.generateBase(opts)
+	sorted, err := r.sortComponents()
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := collectNamespaces(opts, sorted)
+	if err != nil {
+		return err
+	}
+
+	dirs := append([]string{opts.GetBaseDir()}, opts.GetGroupDirs()...)
+	if opts.GetLandscapeDir() != "" {
+		dirs = append(dirs, opts.GetLandscapeDir())
+	}
+	if err := verifyChecksums(opts, dirs); err != nil {
+		return err
 	}
-	return r.generateLandscape(opts)
+
+	recordingFs := files.NewRecordingFs(opts.GetFilesystem().Fs)
+	opts = &generateOptions{
+		Options:            opts,
+		filesystem:         afero.Afero{Fs: recordingFs},
+		namespaces:         namespaces,
+		fluxKustomizations: collectFluxKustomizations(opts, sorted),
+	}
+
+	if err := r.generateFiles(opts, sorted, dirs, recordingFs); err != nil {
+		// The checksums of the files written before the failure have to be recorded nevertheless, as their defaults would be rejected
+		// as changed by the next generation otherwise.
+		if checksumsErr := updateChecksums(opts, dirs, recordingFs.WrittenFiles()); checksumsErr != nil {
+			return errors.Join(err, checksumsErr)
+		}
+		return err
+	}
+	return nil
 }
 
-func (r *registry) generateBase(opts Options) error {
-	for _, component := range r.components {
-		if err := component.GenerateBase(opts); err != nil {
+// generateFiles generates the base and landscape directories of the sorted components and records the checksums of the generated files.
+func (r *registry) generateFiles(opts Options, sorted []Interface, dirs []string, recordingFs *files.RecordingFs) error {
+	if err := r.generate(sorted, func(component Interface) error {
+		if err := component.GenerateBase(newComponentOptions(opts, component)); err != nil {
+			return fmt.Errorf("failed generating base of component %q: %w", component.Name(), err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if opts.GetLandscapeDir() == "" {
+		if err := reportSharedOrphanedFiles(opts, opts.GetBaseDir(), recordingFs.WrittenFiles()); err != nil {
 			return err
 		}
+		return writeChecksums(opts, dirs)
+	}
+
+	if err := r.generate(sorted, func(component Interface) error {
+		if err := component.GenerateLandscape(newComponentOptions(opts, component)); err != nil {
+			return fmt.Errorf("failed generating landscape of component %q: %w", component.Name(), err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := writeLandscapeComponentsKustomizations(opts); err != nil {
+		return err
+	}
+	if err := writeSOPSConfig(opts); err != nil {
+		return err
+	}
+
+	written := recordingFs.WrittenFiles()
+	for _, dir := range append([]string{opts.GetBaseDir()}, opts.GetGroupDirs()...) {
+		if err := reportSharedOrphanedFiles(opts, dir, written); err != nil {
+			return err
+		}
+	}
+	pruned, err := r.handleOrphanedFiles(opts, opts.GetLandscapeDir(), written)
+	if err != nil {
+		return err
+	}
+	if pruned {
+		// Remove pruned directories from the parent kustomizations.
+		if err := writeLandscapeComponentsKustomizations(opts); err != nil {
+			return err
+		}
+	}
+	if err := writeChecksums(opts, dirs); err != nil {
+		return err
+	}
+	return reportUndefinedVariables(opts)
+}
+
+// verifyChecksums reports the generated files in the given directories that have been edited since the last generation.
+// It returns an error if defaults have been changed, as merging with them would silently drop or revert modifications.
+func verifyChecksums(opts Options, dirs []string) error {
+	var tampered []string
+	for _, dir := range dirs {
+		report, err := files.VerifyChecksums(dir, opts.GetFilesystem())
+		if err != nil {
+			return fmt.Errorf("failed verifying checksums of %s: %w", dir, err)
+		}
+		for _, edited := range report.EditedFiles {
+			opts.GetLogger().Info("File has been edited since the last generation, merging the modifications", "file", edited)
+		}
+		tampered = append(tampered, report.TamperedDefaults...)
+	}
+	if len(tampered) > 0 {
+		return fmt.Errorf("defaults have been changed since the last generation, restore them or remove them to merge the generated files from scratch: %s", strings.Join(tampered, ", "))
+	}
+	return nil
+}
+
+// writeChecksums records the checksums of the generated files in the given directories for the next generation.
+func writeChecksums(opts Options, dirs []string) error {
+	for _, dir := range dirs {
+		if err := files.WriteChecksums(dir, opts.GetFilesystem()); err != nil {
+			return fmt.Errorf("failed writing checksums of %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// collectFluxKustomizations returns the references to the Flux Kustomizations declared by the given components, keyed by the component
+// names.
+func collectFluxKustomizations(opts Options, components []Interface) map[string][]kustomizev1.DependencyReference {
+	fluxKustomizations := make(map[string][]kustomizev1.DependencyReference)
+	for _, component := range components {
+		if declarer, ok := component.(FluxKustomizationDeclarer); ok {
+			fluxKustomizations[component.Name()] = declarer.FluxKustomizations(newComponentOptions(opts, component))
+		}
+	}
+	return fluxKustomizations
+}
+
+// updateChecksums records the checksums of the files written into the given directories so far. The recorded checksums of all other
+// files are kept, so that their changes are still detected by the next generation.
+func updateChecksums(opts Options, dirs []string, written sets.Set[string]) error {
+	for _, dir := range dirs {
+		defaultsDir := path.Join(dir, files.GLKSystemDirName, files.DefaultDirName) + "/"
+		var relativePaths []string
+		for _, filePath := range sets.List(written) {
+			if relativePath, ok := strings.CutPrefix(filePath, defaultsDir); ok {
+				relativePaths = append(relativePaths, relativePath)
+			}
+		}
+		if err := files.UpdateChecksums(dir, opts.GetFilesystem(), relativePaths...); err != nil {
+			return fmt.Errorf("failed updating checksums of %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// reportSharedOrphanedFiles reports the files in the given base or group directory that have been generated previously, but not in this run.
+// The base and group directories are shared by multiple landscapes, whose configurations determine the files generated into them, e.g.
+// the configured charts or whether the OCM components have been resolved. Hence, their orphaned files might still be used by other
+// landscapes and are never pruned.
+func reportSharedOrphanedFiles(opts Options, dir string, written sets.Set[string]) error {
+	orphans, err := files.FindOrphanedFiles(dir, written, opts.GetFilesystem())
+	if err != nil {
+		return fmt.Errorf("failed finding orphaned files in %s: %w", dir, err)
+	}
+
+	for _, orphan := range orphans {
+		opts.GetLogger().Info("File in shared directory is no longer generated for this landscape, remove it and its default manually if no other landscape uses it",
+			"file", orphan.Path, "default", orphan.DefaultPath)
 	}
 	return nil
 }
 
-func (r *registry) generateLandscape(opts Options) error {
+// handleOrphanedFiles reports the files in the given landscape directory that have been generated previously, but not in this run.
+// If pruning is enabled, files that have not been modified by the user are removed. It returns whether any file was removed.
+func (r *registry) handleOrphanedFiles(opts Options, dir string, written sets.Set[string]) (bool, error) {
+	orphans, err := files.FindOrphanedFiles(dir, written, opts.GetFilesystem())
+	if err != nil {
+		return false, fmt.Errorf("failed finding orphaned files in %s: %w", dir, err)
+	}
+
+	var pruned bool
+	for _, orphan := range orphans {
+		log := opts.GetLogger().WithValues("file", orphan.Path)
+		switch {
+		case !r.prune:
+			log.Info("File is no longer generated, use --prune to remove it", "modified", orphan.Modified)
+		case orphan.Modified:
+			log.Info("Warning: File is no longer generated but has been modified, keeping it. Remove it and its default manually", "default", orphan.DefaultPath)
+		default:
+			if err := files.PruneOrphanedFile(dir, orphan, opts.GetFilesystem()); err != nil {
+				return pruned, fmt.Errorf("failed pruning orphaned file %s: %w", orphan.Path, err)
+			}
+			log.Info("Pruned file that is no longer generated")
+			pruned = true
+		}
+	}
+	return pruned, nil
+}
+
+type generateOptions struct {
+	Options
+
+	filesystem afero.Afero
+	namespaces []Namespace
+	// fluxKustomizations are the references to the Flux Kustomizations of the components implementing FluxKustomizationDeclarer.
+	fluxKustomizations map[string][]kustomizev1.DependencyReference
+}
+
+// GetFilesystem returns the filesystem to use.
+func (o *generateOptions) GetFilesystem() afero.Afero {
+	return o.filesystem
+}
+
+// GetNamespaces returns the namespaces declared by all components and the configuration, sorted by name.
+func (o *generateOptions) GetNamespaces() []Namespace {
+	return o.namespaces
+}
+
+type generateResult struct {
+	index int
+	err   error
+}
+
+// generate calls the given function for all components with at most r.workers concurrent calls.
+// The components must be sorted topologically. Components are only passed to the function once all their dependencies succeeded.
+// If multiple components are ready at the same time, they are processed in the given order.
+func (r *registry) generate(sorted []Interface, generateFunc func(Interface) error) error {
+	var (
+		indices    = make(map[string]int, len(sorted))
+		pending    = make([]int, len(sorted))
+		dependents = make([][]int, len(sorted))
+		errs       = make([]error, len(sorted))
+		ready      []int
+	)
+	for i, component := range sorted {
+		indices[component.Name()] = i
+	}
+	for i, component := range sorted {
+		for _, dependency := range component.Dependencies() {
+			pending[i]++
+			dependents[indices[dependency]] = append(dependents[indices[dependency]], i)
+		}
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		work    = make(chan int)
+		results = make(chan generateResult)
+	)
+	for range max(r.workers, 1) {
+		wg.Go(func() {
+			for i := range work {
+				results <- generateResult{index: i, err: generateFunc(sorted[i])}
+			}
+		})
+	}
+
+	for running := 0; len(ready) > 0 || running > 0; {
+		var (
+			next      chan<- int
+			nextIndex int
+		)
+		if len(ready) > 0 {
+			next, nextIndex = work, ready[0]
+		}
+
+		select {
+		case next <- nextIndex:
+			ready = ready[1:]
+			running++
+		case result := <-results:
+			running--
+			if result.err != nil {
+				// Dependents of a failed component are never generated.
+				errs[result.index] = result.err
+				continue
+			}
+			for _, dependent := range dependents[result.index] {
+				pending[dependent]--
+				if pending[dependent] == 0 {
+					idx, _ := slices.BinarySearch(ready, dependent)
+					ready = slices.Insert(ready, idx, dependent)
+				}
+			}
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// sortComponents returns the registered components in topological order of their dependencies.
+// It returns an error if a dependency is not registered or if the dependencies contain a cycle.
+func (r *registry) sortComponents() ([]Interface, error) {
+	registered := make(map[string]Interface, len(r.components))
 	for _, component := range r.components {
-		if err := component.GenerateLandscape(opts); err != nil {
-			return err
+		registered[component.Name()] = component
+	}
+	for _, component := range r.components {
+		for _, dependency := range component.Dependencies() {
+			dependencyComponent, ok := registered[dependency]
+			if !ok {
+				return nil, fmt.Errorf("component %q depends on unknown component %q", component.Name(), dependency)
+			}
+			// A layer only becomes ready after all its components, hence components must not depend on components of later layers.
+			if layerIndex(dependencyComponent.Layer()) > layerIndex(component.Layer()) {
+				return nil, fmt.Errorf("component %q in layer %q depends on component %q in later layer %q",
+					component.Name(), component.Layer(), dependency, dependencyComponent.Layer())
+			}
+		}
+	}
+
+	var (
+		sorted    = make([]Interface, 0, len(r.components))
+		done      = make(map[string]struct{}, len(r.components))
+		remaining = slices.Clone(r.components)
+	)
+	for len(remaining) > 0 {
+		idx := slices.IndexFunc(remaining, func(component Interface) bool {
+			for _, dependency := range component.Dependencies() {
+				if _, ok := done[dependency]; !ok {
+					return false
+				}
+			}
+			return true
+		})
+		if idx == -1 {
+			var names []string
+			for _, component := range remaining {
+				names = append(names, component.Name())
+			}
+			slices.Sort(names)
+			return nil, fmt.Errorf("dependency cycle detected between components: %s", strings.Join(names, ", "))
 		}
+
+		sorted = append(sorted, remaining[idx])
+		done[remaining[idx].Name()] = struct{}{}
+		remaining = slices.Delete(remaining, idx, idx+1)
 	}
-	return writeLandscapeComponentsKustomizations(opts)
+
+	return sorted, nil
 }
 
-// NewRegistry creates a new component registry.
-func NewRegistry() Registry {
+// NewRegistry creates a new component registry which generates at most the given number of components concurrently.
+// If prune is true, previously generated files that are no longer produced are removed unless they have been modified by the user.
+func NewRegistry(workers int, prune bool) Registry {
 	return &registry{
 		components: []Interface{},
+		workers:    workers,
+		prune:      prune,
 	}
 }
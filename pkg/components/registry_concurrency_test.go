@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components_test
+
+import (
+	"os"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/cloudprofiles"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/extensions"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/gardeneroperator"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/helmcharts"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/seeds"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+)
+
+var _ = Describe("Registry", func() {
+	Describe("#Generate", func() {
+		It("should produce the same output for all components when generating them concurrently", func() {
+			// The dashboard is not generated, as its OCM component in the test data does not contain a chart.
+			ocmComponents := test.TestdataFixture("../ocm/components/testdata").Resolve()
+			config := &configv1alpha1.LandscapeKitConfiguration{
+				Secrets: &configv1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}},
+				Components: []configv1alpha1.ComponentConfig{
+					{Name: helmcharts.ComponentName, Config: &runtime.RawExtension{Raw: []byte(`{"charts": [
+  {"name": "cert-manager", "repository": "oci://quay.io/jetstack/charts", "chart": "cert-manager", "version": "v1.16.0", "namespace": "cert-manager"},
+  {"name": "my-operator", "repository": "https://charts.example.com", "chart": "operator", "version": "1.0.0", "namespace": "my-operator", "layer": "runtime"}
+]}`)}},
+					{Name: gardeneroperator.ComponentName, Features: []string{gardeneroperator.FeatureHighAvailability}},
+					{Name: garden.ComponentName, Config: &runtime.RawExtension{Raw: []byte(`{
+  "runtimeCluster": {
+    "networking": {"pods": ["10.1.0.0/16"], "services": ["10.2.0.0/16"], "nodes": ["10.0.0.0/16"]},
+    "ingressDomains": [{"name": "ingress.runtime.example.com", "provider": "primary"}],
+    "provider": {"region": "eu-west-1", "zones": ["eu-west-1a", "eu-west-1b"]}
+  },
+  "virtualCluster": {
+    "domains": [{"name": "garden.example.com", "provider": "primary"}],
+    "networking": {"services": ["100.64.0.0/13"]},
+    "kubernetesVersion": "1.33.4",
+    "clusterIdentity": "landscape-dev"
+  },
+  "dns": {"providers": [{"name": "primary", "type": "aws-route53", "secretRef": {"name": "dns-credentials"}}]},
+  "etcdBackup": {"provider": "aws", "bucketName": "garden-backup", "region": "eu-west-1", "secretRef": {"name": "backup-credentials"}}
+}`)}},
+					{Name: cloudprofiles.ComponentName, Config: &runtime.RawExtension{Raw: []byte(`{"cloudProfiles": [{"name": "aws", "type": "aws"}]}`)}},
+					{Name: extensions.ComponentName, Config: &runtime.RawExtension{Raw: []byte(`{"extensions": [{
+  "name": "shoot-cert-service",
+  "resources": [{"kind": "Extension", "type": "shoot-cert-service"}]
+}]}`)}},
+					{Name: seeds.ComponentName, Config: &runtime.RawExtension{Raw: []byte(`{"seeds": [
+  {
+    "name": "aws-eu1",
+    "spec": {
+      "provider": {"type": "aws", "region": "eu-west-1"},
+      "dns": {"provider": {"type": "aws-route53", "secretRef": {"name": "dns", "namespace": "garden"}}},
+      "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"},
+      "backup": {"provider": "aws", "credentialsRef": {"apiVersion": "v1", "kind": "Secret", "name": "backup-aws-eu1", "namespace": "garden"}}
+    }
+  },
+  {
+    "name": "gcp-eu1",
+    "spec": {"provider": {"type": "gcp", "region": "europe-west1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}
+  }
+]}`)}},
+				},
+			}
+
+			generate := func(workers int) afero.Afero {
+				fs := afero.Afero{Fs: afero.NewMemMapFs()}
+				registry := components.NewRegistry(workers, false)
+				for _, component := range []components.Interface{
+					flux.NewComponent(),
+					helmcharts.NewComponent(),
+					gardeneroperator.NewComponent(),
+					garden.NewComponent(),
+					cloudprofiles.NewComponent(),
+					extensions.NewComponent(),
+					seeds.NewComponent(),
+				} {
+					Expect(registry.RegisterComponent(component)).To(Succeed())
+				}
+				Expect(registry.Generate(components.NewOptions(test.BaseDir, nil, test.LandscapeDir, config, ocmComponents, fs, logr.Discard()))).To(Succeed())
+				return fs
+			}
+
+			serial, concurrent := generate(1), generate(8)
+
+			var filePaths []string
+			Expect(serial.Walk("/repo", func(filePath string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				filePaths = append(filePaths, filePath)
+				expected, err := serial.ReadFile(filePath)
+				Expect(err).NotTo(HaveOccurred())
+				actual, err := concurrent.ReadFile(filePath)
+				Expect(err).NotTo(HaveOccurred(), filePath)
+				Expect(string(actual)).To(Equal(string(expected)), filePath)
+				return nil
+			})).To(Succeed())
+			Expect(concurrent.Walk("/repo", func(filePath string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				Expect(filePaths).To(ContainElement(filePath))
+				return nil
+			})).To(Succeed())
+
+			for _, filePath := range []string{
+				"/repo/base/components/infrastructure/chart-cert-manager/helmrelease.yaml",
+				"/repo/base/components/runtime/gardener-operator/helmrelease.yaml",
+				"/repo/base/components/extensions/shoot-cert-service/extension.yaml",
+				"/repo/base/components/seeds/gcp-eu1/gardenlet.yaml",
+				"/repo/landscape/components/virtual-garden/garden/resources/garden.yaml",
+				"/repo/landscape/components/virtual-garden/cloud-profiles/resources/cloudprofile-aws.yaml",
+			} {
+				Expect(serial.Exists(filePath)).To(BeTrue(), filePath)
+			}
+		})
+	})
+})
@@ -0,0 +1,360 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sync"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		reg  Registry
+		opts Options
+
+		lock      sync.Mutex
+		generated []string
+		dependsOn map[string][]kustomizev1.DependencyReference
+		failing   map[string]bool
+	)
+
+	BeforeEach(func() {
+		reg = NewRegistry(1, false)
+		opts = NewOptions("/baseDir", nil, "", nil, nil, afero.Afero{Fs: afero.NewMemMapFs()}, logr.Discard())
+
+		generated = nil
+		dependsOn = make(map[string][]kustomizev1.DependencyReference)
+		failing = make(map[string]bool)
+	})
+
+	newComponent := func(name string, dependencies ...string) Interface {
+		return &fakeComponent{
+			name:         name,
+			dependencies: dependencies,
+			generate: func(options Options) error {
+				lock.Lock()
+				defer lock.Unlock()
+
+				generated = append(generated, name)
+				dependsOn[name] = options.GetFluxDependsOn()
+				if failing[name] {
+					return fmt.Errorf("%s failed", name)
+				}
+				return nil
+			},
+		}
+	}
+
+	Describe("#RegisterComponent", func() {
+		It("should reject components with duplicate names", func() {
+			Expect(reg.RegisterComponent(newComponent("a"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("a"))).To(MatchError(ContainSubstring(`component "a" is already registered`)))
+		})
+
+		It("should reject components without a name", func() {
+			Expect(reg.RegisterComponent(newComponent(""))).To(MatchError(ContainSubstring("must not be empty")))
+		})
+
+		It("should reject components with an unknown layer", func() {
+			Expect(reg.RegisterComponent(&fakeComponent{name: "a", layer: "unknown"})).To(MatchError(`component "a" is assigned to unknown layer "unknown"`))
+		})
+	})
+
+	Describe("#Generate", func() {
+		It("should generate components in topological order", func() {
+			Expect(reg.RegisterComponent(newComponent("c", "b"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("b", "a"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("d"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("a"))).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(Succeed())
+			Expect(generated).To(Equal([]string{"d", "a", "b", "c"}))
+		})
+
+		It("should pass the Flux dependencies of the declared dependencies", func() {
+			Expect(reg.RegisterComponent(newComponent("a"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("b", "a"))).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(Succeed())
+			Expect(dependsOn["a"]).To(BeEmpty())
+			Expect(dependsOn["b"]).To(ConsistOf(kustomizev1.DependencyReference{Name: "a", Namespace: "flux-system"}))
+		})
+
+		It("should fail for unknown dependencies", func() {
+			Expect(reg.RegisterComponent(newComponent("a", "unknown"))).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(MatchError(`component "a" depends on unknown component "unknown"`))
+			Expect(generated).To(BeEmpty())
+		})
+
+		It("should fail for dependencies on components of later layers", func() {
+			Expect(reg.RegisterComponent(&fakeComponent{name: "a", layer: LayerExtensions})).To(Succeed())
+			Expect(reg.RegisterComponent(&fakeComponent{name: "b", layer: LayerRuntime, dependencies: []string{"a"}})).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(MatchError(`component "b" in layer "runtime" depends on component "a" in later layer "extensions"`))
+		})
+
+		It("should allow dependencies on components of the same or earlier layers", func() {
+			Expect(reg.RegisterComponent(&fakeComponent{name: "a", layer: LayerNone, generate: func(Options) error { return nil }})).To(Succeed())
+			Expect(reg.RegisterComponent(&fakeComponent{name: "b", layer: LayerRuntime, dependencies: []string{"a"}, generate: func(Options) error { return nil }})).To(Succeed())
+			Expect(reg.RegisterComponent(&fakeComponent{name: "c", layer: LayerRuntime, dependencies: []string{"b"}, generate: func(Options) error { return nil }})).To(Succeed())
+			Expect(reg.RegisterComponent(&fakeComponent{name: "d", layer: LayerExtensions, dependencies: []string{"c"}, generate: func(Options) error { return nil }})).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(Succeed())
+		})
+
+		It("should fail for dependency cycles", func() {
+			Expect(reg.RegisterComponent(newComponent("a"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("b", "c"))).To(Succeed())
+			Expect(reg.RegisterComponent(newComponent("c", "b"))).To(Succeed())
+
+			Expect(reg.Generate(opts)).To(MatchError("dependency cycle detected between components: b, c"))
+			Expect(generated).To(BeEmpty())
+		})
+
+		Context("concurrent generation", func() {
+			BeforeEach(func() {
+				reg = NewRegistry(4, false)
+
+				for i := range 20 {
+					var dependencies []string
+					if i >= 2 {
+						dependencies = append(dependencies, fmt.Sprintf("component-%02d", i/2))
+					}
+					Expect(reg.RegisterComponent(newComponent(fmt.Sprintf("component-%02d", i), dependencies...))).To(Succeed())
+				}
+			})
+
+			It("should generate all components after their dependencies", func() {
+				Expect(reg.Generate(opts)).To(Succeed())
+
+				Expect(generated).To(HaveLen(20))
+				for i := 2; i < 20; i++ {
+					dependent := fmt.Sprintf("component-%02d", i)
+					dependency := fmt.Sprintf("component-%02d", i/2)
+					Expect(slices.Index(generated, dependency)).To(BeNumerically("<", slices.Index(generated, dependent)), dependent)
+				}
+			})
+
+			It("should skip dependents of failed components and aggregate errors deterministically", func() {
+				failing["component-09"] = true
+				failing["component-03"] = true
+				failing["component-05"] = true
+
+				err := reg.Generate(opts)
+				Expect(err).To(MatchError(`failed generating base of component "component-03": component-03 failed` + "\n" +
+					`failed generating base of component "component-05": component-05 failed` + "\n" +
+					`failed generating base of component "component-09": component-09 failed`))
+				Expect(errors.Unwrap(err)).To(BeNil())
+
+				Expect(generated).To(ConsistOf(
+					"component-00", "component-01", "component-02", "component-03", "component-04",
+					"component-05", "component-08", "component-09", "component-16", "component-17",
+				))
+			})
+		})
+
+		Context("orphaned files", func() {
+			var fs afero.Afero
+
+			newLandscapeComponent := func(name string) Interface {
+				return &fakeComponent{
+					name: name,
+					generate: func(options Options) error {
+						return files.WriteObjectsToFilesystem(map[string][]byte{
+							"flux-kustomization.yaml": []byte("name: " + name + "\n"),
+							"config.yaml":             []byte("key: value\n"),
+						}, options.GetLandscapeDir(), path.Join(DirName, name), options.GetFilesystem())
+					},
+				}
+			}
+
+			generate := func(prune bool, names ...string) {
+				reg := NewRegistry(1, prune)
+				for _, name := range names {
+					Expect(reg.RegisterComponent(newLandscapeComponent(name))).To(Succeed())
+				}
+				Expect(reg.Generate(NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, fs, logr.Discard()))).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				fs = afero.Afero{Fs: afero.NewMemMapFs()}
+				generate(false, "a", "b", "c")
+			})
+
+			It("should keep orphaned files if pruning is disabled", func() {
+				generate(false, "a")
+
+				Expect(fs.Exists("/landscapeDir/components/b/config.yaml")).To(BeTrue())
+				Expect(fs.Exists("/landscapeDir/components/c/config.yaml")).To(BeTrue())
+			})
+
+			It("should prune unmodified orphaned files and update the parent kustomization", func() {
+				Expect(fs.WriteFile("/landscapeDir/components/c/config.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+
+				generate(true, "a")
+
+				Expect(fs.DirExists("/landscapeDir/components/b")).To(BeFalse())
+				Expect(fs.DirExists("/landscapeDir/.glk/defaults/components/b")).To(BeFalse())
+				Expect(fs.Exists("/landscapeDir/components/c/flux-kustomization.yaml")).To(BeFalse())
+				Expect(fs.ReadFile("/landscapeDir/components/c/config.yaml")).To(Equal([]byte("key: changed\n")))
+				Expect(fs.Exists("/landscapeDir/.glk/defaults/components/c/config.yaml")).To(BeTrue())
+
+				content, err := fs.ReadFile("/landscapeDir/components/kustomization.yaml")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("a/flux-kustomization.yaml"))
+				Expect(string(content)).NotTo(ContainSubstring("b/flux-kustomization.yaml"))
+			})
+
+			It("should not prune orphaned files in the shared base directory", func() {
+				writeBase := func(options Options) error {
+					return files.WriteObjectsToFilesystem(map[string][]byte{"chart.yaml": []byte("name: chart\n")}, options.GetBaseDir(), path.Join(DirName, "chart"), options.GetFilesystem())
+				}
+				reg := NewRegistry(1, false)
+				Expect(reg.RegisterComponent(&fakeComponent{name: "chart", generate: writeBase})).To(Succeed())
+				Expect(reg.Generate(NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, fs, logr.Discard()))).To(Succeed())
+
+				// Another landscape sharing the base does not generate the chart.
+				generate(true, "a")
+
+				Expect(fs.ReadFile("/baseDir/components/chart/chart.yaml")).To(Equal([]byte("name: chart\n")))
+				Expect(fs.Exists("/baseDir/.glk/defaults/components/chart/chart.yaml")).To(BeTrue())
+			})
+		})
+
+		Context("checksums", func() {
+			var (
+				fs  afero.Afero
+				reg Registry
+			)
+
+			BeforeEach(func() {
+				fs = afero.Afero{Fs: afero.NewMemMapFs()}
+				opts = NewOptions("/baseDir", nil, "/landscapeDir", nil, nil, fs, logr.Discard())
+				reg = NewRegistry(1, false)
+				Expect(reg.RegisterComponent(&fakeComponent{
+					name: "a",
+					generate: func(options Options) error {
+						return files.WriteObjectsToFilesystem(map[string][]byte{"config.yaml": []byte("key: value\n")}, options.GetLandscapeDir(), path.Join(DirName, "a"), options.GetFilesystem())
+					},
+				})).To(Succeed())
+				Expect(reg.Generate(opts)).To(Succeed())
+			})
+
+			It("should record the checksums of the generated files", func() {
+				Expect(fs.Exists("/landscapeDir/.glk/checksums.yaml")).To(BeTrue())
+				Expect(files.VerifyChecksums("/landscapeDir", fs)).To(Equal(&files.ChecksumsReport{}))
+			})
+
+			It("should produce identical output when generating again", func() {
+				before := map[string][]byte{}
+				Expect(fs.Walk("/landscapeDir", func(filePath string, info os.FileInfo, err error) error {
+					if err != nil || info.IsDir() {
+						return err
+					}
+					before[filePath], err = fs.ReadFile(filePath)
+					return err
+				})).To(Succeed())
+
+				Expect(reg.Generate(opts)).To(Succeed())
+
+				for filePath, content := range before {
+					Expect(fs.ReadFile(filePath)).To(Equal(content), filePath)
+				}
+			})
+
+			It("should accept the defaults written by a failed generation in the next generation", func() {
+				var (
+					value   = "initial"
+					failing = false
+				)
+				configMap := func(value string) []byte {
+					return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\ndata:\n  key: " + value + "\n")
+				}
+				reg := NewRegistry(1, false)
+				Expect(reg.RegisterComponent(&fakeComponent{
+					name: "c",
+					generate: func(options Options) error {
+						return files.WriteObjectsToFilesystem(map[string][]byte{"config.yaml": configMap(value)}, options.GetLandscapeDir(), path.Join(DirName, "c"), options.GetFilesystem())
+					},
+				})).To(Succeed())
+				Expect(reg.RegisterComponent(&fakeComponent{
+					name:         "d",
+					dependencies: []string{"c"},
+					generate: func(Options) error {
+						if failing {
+							return errors.New("d failed")
+						}
+						return nil
+					},
+				})).To(Succeed())
+
+				Expect(reg.Generate(opts)).To(Succeed())
+
+				failing, value = true, "changed"
+				Expect(reg.Generate(opts)).To(MatchError(ContainSubstring("d failed")))
+				Expect(fs.ReadFile("/landscapeDir/components/c/config.yaml")).To(Equal(configMap("changed")))
+				Expect(files.VerifyChecksums("/landscapeDir", fs)).To(Equal(&files.ChecksumsReport{}))
+
+				failing, value = false, "rerun"
+				Expect(reg.Generate(opts)).To(Succeed())
+				Expect(fs.ReadFile("/landscapeDir/components/c/config.yaml")).To(Equal(configMap("rerun")))
+			})
+
+			It("should merge edited files", func() {
+				Expect(fs.WriteFile("/landscapeDir/components/a/config.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+
+				Expect(reg.Generate(opts)).To(Succeed())
+				Expect(fs.ReadFile("/landscapeDir/components/a/config.yaml")).To(Equal([]byte("key: changed\n")))
+			})
+
+			It("should fail if a default has been changed", func() {
+				Expect(fs.WriteFile("/landscapeDir/.glk/defaults/components/a/config.yaml", []byte("key: tampered\n"), 0600)).To(Succeed())
+
+				Expect(reg.Generate(opts)).To(MatchError(ContainSubstring("defaults have been changed since the last generation")))
+				Expect(fs.ReadFile("/landscapeDir/.glk/defaults/components/a/config.yaml")).To(Equal([]byte("key: tampered\n")))
+			})
+		})
+	})
+})
+
+type fakeComponent struct {
+	name         string
+	dependencies []string
+	layer        Layer
+	generate     func(Options) error
+}
+
+func (c *fakeComponent) Name() string {
+	return c.name
+}
+
+func (c *fakeComponent) Dependencies() []string {
+	return c.dependencies
+}
+
+func (c *fakeComponent) Layer() Layer {
+	return c.layer
+}
+
+func (c *fakeComponent) GenerateBase(options Options) error {
+	return c.generate(options)
+}
+
+func (c *fakeComponent) GenerateLandscape(options Options) error {
+	return c.generate(options)
+}
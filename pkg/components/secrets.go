@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
+const (
+	// SOPSConfigFileName is the name of the SOPS configuration file generated into the landscape directory.
+	// Its creation rule encrypts the secrets for the configured age recipients.
+	SOPSConfigFileName = ".sops.yaml"
+	// DecryptionSecretName is the name of the secret containing the age private key, which Flux decrypts the secrets with.
+	// It must be created manually in the namespace of each Flux Kustomization applying secrets, i.e. flux-system and
+	// garden for the Flux Kustomizations targeting the virtual garden.
+	DecryptionSecretName = "sops-age"
+
+	// secretFileRegex matches the files of the secrets, see Secret.FileName.
+	secretFileRegex = `secret-[^/]+\.sops\.yaml$`
+	// encryptedRegex matches the fields of the secrets that are encrypted by SOPS.
+	encryptedRegex = `^(data|stringData)$`
+)
+
+var secretFilePattern = regexp.MustCompile(secretFileRegex)
+
+// Secret is a secret required by a component, e.g. the credentials of a DNS provider.
+type Secret struct {
+	// Name is the name of the secret.
+	Name string
+	// Namespace is the namespace of the secret.
+	Namespace string
+	// Type is the type of the secret, e.g. kubernetes.io/dockerconfigjson for registry credentials. Defaults to Opaque.
+	Type corev1.SecretType
+	// Description describes the expected content of the secret. It is added as comment to the scaffolded secret.
+	Description string
+	// Dir is the Flux managed directory of the component the secret is applied by, see WriteFluxComponent.
+	// Defaults to the directory of the component, see ComponentDir.
+	Dir string
+}
+
+// FileName returns the name of the file containing the secret.
+func (s Secret) FileName() string {
+	return "secret-" + s.Name + ".sops.yaml"
+}
+
+// SecretDeclarer is implemented by components that require secrets in the landscape.
+// The declared secrets are only scaffolded if secrets are configured, see Options.GetSecrets.
+type SecretDeclarer interface {
+	// Secrets returns the secrets required by the component.
+	Secrets(Options) []Secret
+}
+
+// declaredSecrets returns the secrets declared by the given component with the directory defaulted.
+// It returns nil if secrets are not configured.
+func declaredSecrets(opts Options, component Interface) []Secret {
+	declarer, ok := component.(SecretDeclarer)
+	if !ok || opts.GetConfig().Secrets == nil {
+		return nil
+	}
+
+	secrets := slices.Clone(declarer.Secrets(opts))
+	for i := range secrets {
+		if secrets[i].Dir == "" {
+			secrets[i].Dir = ComponentDir(component.Layer(), component.Name())
+		}
+	}
+	return secrets
+}
+
+// writeSecrets scaffolds the secrets of the generated component applied from the given Flux managed directory into its resources
+// directory and returns their file names. The secrets are only written if they do not exist yet, as they are encrypted and filled in
+// by the user afterwards. Secrets that have not been encrypted yet are reported.
+func writeSecrets(opts Options, dir, resourcesDir string) ([]string, error) {
+	var fileNames []string
+	for _, secret := range opts.GetSecrets() {
+		if secret.Dir != dir {
+			continue
+		}
+
+		content, err := newSecretStub(secret)
+		if err != nil {
+			return nil, err
+		}
+		filePath := path.Join(opts.GetLandscapeDir(), resourcesDir, secret.FileName())
+		if err := files.WriteFileToFilesystem(content, filePath, false, opts.GetFilesystem()); err != nil {
+			return nil, err
+		}
+		encrypted, err := IsSecretEncrypted(filePath, opts.GetFilesystem())
+		if err != nil {
+			return nil, err
+		}
+		if !encrypted {
+			opts.GetLogger().Info("Secret is not encrypted yet, fill in its values and encrypt it with SOPS", "file", filePath)
+		}
+		fileNames = append(fileNames, secret.FileName())
+	}
+	return fileNames, nil
+}
+
+// newSecretStub returns the manifest of the given secret without data, including instructions how to fill in and encrypt it.
+func newSecretStub(secret Secret) ([]byte, error) {
+	secretType := secret.Type
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+	content, err := yaml.Marshal(map[string]any{
+		"apiVersion": corev1.SchemeGroupVersion.String(),
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      secret.Name,
+			"namespace": secret.Namespace,
+		},
+		"type":       secretType,
+		"stringData": map[string]any{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var header strings.Builder
+	if secret.Description != "" {
+		fmt.Fprintf(&header, "# %s\n", secret.Description)
+	}
+	fmt.Fprintf(&header, "# Fill in the stringData and encrypt the file before committing it: sops --encrypt --in-place %s\n", secret.FileName())
+	return append([]byte(header.String()), content...), nil
+}
+
+// IsSecretFile returns whether the file at the given path contains a secret scaffolded for the components, see Secret.FileName.
+func IsSecretFile(filePath string) bool {
+	return secretFilePattern.MatchString(filePath)
+}
+
+// IsSecretEncrypted returns whether the secret file at the given path has been encrypted by SOPS, i.e. whether it contains the SOPS metadata.
+func IsSecretEncrypted(filePath string, fs afero.Afero) (bool, error) {
+	content, err := fs.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	var manifest map[string]any
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return false, fmt.Errorf("failed to parse secret %s: %w", filePath, err)
+	}
+	_, ok := manifest["sops"]
+	return ok, nil
+}
+
+// writeSOPSConfig writes the SOPS configuration into the landscape directory, which encrypts the data of the secrets for the configured
+// age recipients. It does nothing if secrets are not configured.
+func writeSOPSConfig(opts Options) error {
+	secretsConfig := opts.GetConfig().Secrets
+	if secretsConfig == nil {
+		return nil
+	}
+
+	content, err := yaml.Marshal(map[string]any{
+		"creation_rules": []map[string]any{{
+			"path_regex":      secretFileRegex,
+			"encrypted_regex": encryptedRegex,
+			"age":             strings.Join(secretsConfig.AgeRecipients, ","),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return files.WriteObjectsToFilesystem(map[string][]byte{SOPSConfigFileName: content}, opts.GetLandscapeDir(), "", opts.GetFilesystem())
+}
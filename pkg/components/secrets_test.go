@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+var _ = Describe("Secrets", func() {
+	const secretPath = "/landscape/components/virtual-garden/foo/resources/secret-dns.sops.yaml"
+
+	var (
+		fs     afero.Afero
+		config *configv1alpha1.LandscapeKitConfiguration
+
+		component = &secretComponent{
+			fakeComponent: fakeComponent{name: "foo", layer: LayerVirtualGarden},
+			secrets: []Secret{
+				{Name: "dns", Namespace: "garden", Description: "Credentials of the DNS provider."},
+				{Name: "other", Namespace: "garden", Dir: "components/virtual-garden/bar"},
+			},
+		}
+	)
+
+	generate := func() error {
+		registry := NewRegistry(1, false)
+		Expect(registry.RegisterComponent(component)).To(Succeed())
+		return registry.Generate(NewOptions("/base", nil, "/landscape", config, nil, fs, logr.Discard()))
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		config = &configv1alpha1.LandscapeKitConfiguration{
+			Secrets: &configv1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", "age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg"}},
+		}
+	})
+
+	It("should scaffold the declared secrets and decrypt them by the Flux Kustomization", func() {
+		Expect(generate()).To(Succeed())
+
+		Expect(fs.ReadFile(secretPath)).To(Equal([]byte(`# Credentials of the DNS provider.
+# Fill in the stringData and encrypt the file before committing it: sops --encrypt --in-place secret-dns.sops.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: dns
+  namespace: garden
+stringData: {}
+type: Opaque
+`)))
+		Expect(fs.Exists("/landscape/components/virtual-garden/foo/resources/secret-other.sops.yaml")).To(BeFalse())
+		Expect(fs.ReadFile("/landscape/components/virtual-garden/foo/resources/kustomization.yaml")).To(ContainSubstring(`resources:
+  - secret-dns.sops.yaml
+`))
+		Expect(fs.ReadFile("/landscape/components/virtual-garden/foo/flux-kustomization.yaml")).To(ContainSubstring(`  decryption:
+    provider: sops
+    secretRef:
+      name: sops-age
+`))
+		Expect(fs.ReadFile("/landscape/.sops.yaml")).To(MatchYAML(`creation_rules:
+- path_regex: secret-[^/]+\.sops\.yaml$
+  encrypted_regex: ^(data|stringData)$
+  age: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p,age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg
+`))
+	})
+
+	It("should keep secrets that have already been encrypted", func() {
+		encrypted := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: dns
+  namespace: garden
+stringData:
+  token: ENC[AES256_GCM,data:abc,type:str]
+sops:
+  age:
+  - recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+`)
+		Expect(fs.MkdirAll("/landscape/components/virtual-garden/foo/resources", 0700)).To(Succeed())
+		Expect(fs.WriteFile(secretPath, encrypted, 0600)).To(Succeed())
+
+		Expect(generate()).To(Succeed())
+
+		Expect(fs.ReadFile(secretPath)).To(Equal(encrypted))
+	})
+
+	It("should not scaffold secrets if they are not configured", func() {
+		config.Secrets = nil
+
+		Expect(generate()).To(Succeed())
+
+		Expect(fs.Exists(secretPath)).To(BeFalse())
+		Expect(fs.Exists("/landscape/.sops.yaml")).To(BeFalse())
+		Expect(fs.ReadFile("/landscape/components/virtual-garden/foo/flux-kustomization.yaml")).NotTo(ContainSubstring("decryption"))
+	})
+})
+
+type secretComponent struct {
+	fakeComponent
+
+	secrets []Secret
+}
+
+func (c *secretComponent) Secrets(Options) []Secret {
+	return append([]Secret(nil), c.secrets...)
+}
+
+func (c *secretComponent) GenerateBase(Options) error {
+	return nil
+}
+
+func (c *secretComponent) GenerateLandscape(options Options) error {
+	return WriteFluxComponent(options, ComponentDir(c.Layer(), c.Name()), kustomization.FluxKustomizationOptions{Name: c.Name()}, "", nil, nil)
+}
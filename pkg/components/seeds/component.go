@@ -0,0 +1,338 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package seeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	gardencorev1 "github.com/gardener/gardener/pkg/apis/core/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	seedmanagementv1alpha1 "github.com/gardener/gardener/pkg/apis/seedmanagement/v1alpha1"
+	gardenletconfigv1alpha1 "github.com/gardener/gardener/pkg/gardenlet/apis/config/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/garden"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// ComponentName is the name of the seeds component.
+	ComponentName = "seeds"
+
+	// ChartResourceName is the name of the OCM resource containing the gardenlet chart.
+	ChartResourceName = "gardenlet"
+
+	// GardenletFileName is the name of the manifest file containing the Gardenlet resource.
+	GardenletFileName = "gardenlet.yaml"
+	// SeedPatchFileName is the name of the patch file containing the seed settings of the Gardenlet resource.
+	SeedPatchFileName = "seed-patch.yaml"
+)
+
+// Config is the configuration of the seeds component.
+type Config struct {
+	// Seeds are the seeds of the landscape.
+	Seeds []Seed `json:"seeds"`
+}
+
+// Seed is a seed of the landscape, whose gardenlet is deployed by the gardener-operator.
+type Seed struct {
+	// Name is the name of the seed. It is used for the Gardenlet resource, the Seed and the directories.
+	Name string `json:"name"`
+	// KubeconfigSecretName is the name of the secret in the garden namespace of the virtual garden containing the kubeconfig
+	// of the seed cluster. If it is not set, the gardenlet is deployed into the runtime cluster.
+	KubeconfigSecretName string `json:"kubeconfigSecretName,omitempty"`
+	// Labels are the labels of the seed.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the specification of the seed, e.g. its provider, networks, ingress and backup settings.
+	Spec gardencorev1beta1.SeedSpec `json:"spec"`
+}
+
+type component struct{}
+
+// NewComponent creates a new seeds component.
+func NewComponent() components.Interface {
+	return &component{}
+}
+
+// Name returns the component name.
+func (c *component) Name() string {
+	return ComponentName
+}
+
+// Dependencies returns the names of the components this component depends on.
+func (c *component) Dependencies() []string {
+	return nil
+}
+
+// Layer returns the rollout layer of the component.
+func (c *component) Layer() components.Layer {
+	return components.LayerSeeds
+}
+
+// Namespaces returns the namespaces required by the component.
+func (c *component) Namespaces(_ components.Options) []components.Namespace {
+	return []components.Namespace{{Name: flux.GardenNamespaceName, PodSecurityLevel: components.PodSecurityLevelPrivileged}}
+}
+
+// Secrets returns the secrets of the backup and DNS providers referenced by the configured seeds, which are applied to the virtual
+// garden together with the Gardenlet of the first seed referencing them. Secrets without namespace are placed into the garden namespace.
+func (c *component) Secrets(options components.Options) []components.Secret {
+	config, err := decodeConfig(options)
+	if err != nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var (
+		secrets []components.Secret
+		names   = sets.New[string]()
+	)
+	for _, seed := range config.Seeds {
+		addSecret := func(kind, name, namespace, description string) {
+			if (kind != "" && kind != "Secret") || name == "" {
+				return
+			}
+			if namespace == "" {
+				namespace = flux.GardenNamespaceName
+			}
+			if names.Has(namespace + "/" + name) {
+				return
+			}
+			names.Insert(namespace + "/" + name)
+			secrets = append(secrets, components.Secret{Name: name, Namespace: namespace, Description: description, Dir: Dir(seed.Name)})
+		}
+
+		if backup := seed.Spec.Backup; backup != nil && backup.CredentialsRef != nil {
+			addSecret(backup.CredentialsRef.Kind, backup.CredentialsRef.Name, backup.CredentialsRef.Namespace,
+				fmt.Sprintf("Credentials of the %s object store the backups of seed %q are stored in.", backup.Provider, seed.Name))
+		}
+		if dns := seed.Spec.DNS; dns.Provider != nil {
+			addSecret("", dns.Provider.SecretRef.Name, dns.Provider.SecretRef.Namespace,
+				fmt.Sprintf("Credentials of the DNS provider of seed %q of type %s.", seed.Name, dns.Provider.Type))
+		}
+		if dns := seed.Spec.DNS; dns.Internal != nil {
+			addSecret(dns.Internal.CredentialsRef.Kind, dns.Internal.CredentialsRef.Name, dns.Internal.CredentialsRef.Namespace,
+				fmt.Sprintf("Credentials of the DNS provider of the internal domain %s of seed %q of type %s.", dns.Internal.Domain, seed.Name, dns.Internal.Type))
+		}
+		for _, defaultDomain := range seed.Spec.DNS.Defaults {
+			addSecret(defaultDomain.CredentialsRef.Kind, defaultDomain.CredentialsRef.Name, defaultDomain.CredentialsRef.Namespace,
+				fmt.Sprintf("Credentials of the DNS provider of the default domain %s of seed %q of type %s.", defaultDomain.Domain, seed.Name, defaultDomain.Type))
+		}
+	}
+	return secrets
+}
+
+// FluxKustomizations returns the references to the Flux Kustomizations of the configured seeds, which are placed into the garden namespace.
+// Components depending on the seeds component depend on all of them.
+func (c *component) FluxKustomizations(options components.Options) []kustomizev1.DependencyReference {
+	config, err := decodeConfig(options)
+	if err != nil || options.GetOCMComponents() == nil {
+		// The error is returned when generating the component.
+		return nil
+	}
+
+	var references []kustomizev1.DependencyReference
+	for _, seed := range config.Seeds {
+		references = append(references, fluxKustomizationOptions(options, seed).DependencyReference())
+	}
+	return references
+}
+
+// GenerateBase generates a Gardenlet resource for each configured seed into the base directory.
+// The gardenlet chart is taken from the resolved OCM components and the image vector of the Gardener component is passed as image
+// vector overwrite to the gardenlets.
+func (c *component) GenerateBase(options components.Options) error {
+	config, err := decodeConfig(options)
+	if err != nil || len(config.Seeds) == 0 {
+		return err
+	}
+	ocmComponents := options.GetOCMComponents()
+	if ocmComponents == nil {
+		logSkipped(options)
+		return nil
+	}
+
+	cref, chart, err := ocmComponents.FindResource(ocmcomponents.ComponentNameGardener, ChartResourceName, ocmcomponents.ResourceTypeHelmChart)
+	if err != nil {
+		return fmt.Errorf("failed to find the chart of component %q: %w", ComponentName, err)
+	}
+	imageVectorOverwrite, err := ocmComponents.GetImageVectorOverwrite(cref)
+	if err != nil {
+		return fmt.Errorf("failed to get image vector of OCM component %s: %w", cref, err)
+	}
+
+	for _, seed := range config.Seeds {
+		gardenlet, err := newGardenlet(seed.Name, chart.Value, imageVectorOverwrite)
+		if err != nil {
+			return err
+		}
+		content, err := yaml.Marshal(gardenlet)
+		if err != nil {
+			return err
+		}
+		if err := components.WriteBaseManifests(options, Dir(seed.Name), map[string][]byte{GardenletFileName: content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateLandscape generates a Flux managed overlay for each configured seed, which sets the seed settings and the target cluster
+// of the Gardenlet resource. The Gardenlet resources are applied to the virtual garden, from where the gardener-operator deploys the
+// gardenlets into the seed clusters.
+func (c *component) GenerateLandscape(options components.Options) error {
+	config, err := decodeConfig(options)
+	if err != nil || len(config.Seeds) == 0 {
+		return err
+	}
+	if options.GetOCMComponents() == nil {
+		logSkipped(options)
+		return nil
+	}
+
+	for _, seed := range config.Seeds {
+		seedPatch, err := newSeedPatch(seed)
+		if err != nil {
+			return err
+		}
+
+		dir := Dir(seed.Name)
+		if err := components.WriteFluxComponent(options, dir, fluxKustomizationOptions(options, seed), dir, nil, map[string][]byte{SeedPatchFileName: seedPatch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fluxKustomizationOptions returns the options of the Flux Kustomization applying the Gardenlet of the given seed to the virtual garden.
+func fluxKustomizationOptions(options components.Options, seed Seed) kustomization.FluxKustomizationOptions {
+	return garden.TargetVirtualGarden(kustomization.FluxKustomizationOptions{
+		Name:      FluxKustomizationName(seed.Name),
+		SourceRef: flux.LandscapeSourceRef(options),
+	})
+}
+
+// Dir returns the directory of the seed with the given name.
+func Dir(name string) string {
+	return components.ComponentDir(components.LayerSeeds, name)
+}
+
+// FluxKustomizationName returns the name of the Flux Kustomization managing the seed with the given name.
+func FluxKustomizationName(name string) string {
+	return "seed-" + name
+}
+
+func newGardenlet(name, chartRef, imageVectorOverwrite string) (*seedmanagementv1alpha1.Gardenlet, error) {
+	config, err := json.Marshal(metav1.TypeMeta{
+		APIVersion: gardenletconfigv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "GardenletConfiguration",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gardenlet := &seedmanagementv1alpha1.Gardenlet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: seedmanagementv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Gardenlet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: flux.GardenNamespaceName,
+		},
+		Spec: seedmanagementv1alpha1.GardenletSpec{
+			Deployment: seedmanagementv1alpha1.GardenletSelfDeployment{
+				Helm: seedmanagementv1alpha1.GardenletHelm{
+					OCIRepository: gardencorev1.OCIRepository{Ref: ptr.To(chartRef)},
+				},
+			},
+			Config: runtime.RawExtension{Raw: config},
+		},
+	}
+	if imageVectorOverwrite != "" {
+		gardenlet.Spec.Deployment.ImageVectorOverwrite = ptr.To(imageVectorOverwrite)
+	}
+	return gardenlet, nil
+}
+
+// newSeedPatch returns a patch setting the seed template and the kubeconfig of the seed cluster in the Gardenlet resource of the given seed.
+func newSeedPatch(seed Seed) ([]byte, error) {
+	spec := map[string]any{
+		"config": map[string]any{
+			"seedConfig": gardencorev1beta1.SeedTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   seed.Name,
+					Labels: seed.Labels,
+				},
+				Spec: seed.Spec,
+			},
+		},
+	}
+	if seed.KubeconfigSecretName != "" {
+		spec["kubeconfigSecretRef"] = map[string]any{"name": seed.KubeconfigSecretName}
+	}
+
+	return yaml.Marshal(map[string]any{
+		"apiVersion": seedmanagementv1alpha1.SchemeGroupVersion.String(),
+		"kind":       "Gardenlet",
+		"metadata": map[string]any{
+			"name":      seed.Name,
+			"namespace": flux.GardenNamespaceName,
+		},
+		"spec": spec,
+	})
+}
+
+// decodeConfig decodes the configuration of the component and checks that the seeds are named uniquely and all settings without default are given.
+func decodeConfig(options components.Options) (*Config, error) {
+	config := &Config{}
+	if err := components.DecodeComponentConfig(options, config); err != nil {
+		return nil, err
+	}
+
+	names := sets.New[string]()
+	for i, seed := range config.Seeds {
+		if errs := utilvalidation.IsDNS1123Label(seed.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("seed %d of component %q has an invalid name %q: %s", i, ComponentName, seed.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(seed.Name) {
+			return nil, fmt.Errorf("seed %q of component %q is configured more than once", seed.Name, ComponentName)
+		}
+		names.Insert(seed.Name)
+
+		var missing []string
+		for field, isMissing := range map[string]bool{
+			"spec.provider.type":     seed.Spec.Provider.Type == "",
+			"spec.provider.region":   seed.Spec.Provider.Region == "",
+			"spec.networks.pods":     seed.Spec.Networks.Pods == "",
+			"spec.networks.services": seed.Spec.Networks.Services == "",
+		} {
+			if isMissing {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			slices.Sort(missing)
+			return nil, fmt.Errorf("seed %q of component %q is missing %s", seed.Name, ComponentName, strings.Join(missing, ", "))
+		}
+	}
+	return config, nil
+}
+
+func logSkipped(options components.Options) {
+	options.GetLogger().Info("Skipping component as the OCM components have not been resolved", "component", ComponentName)
+}
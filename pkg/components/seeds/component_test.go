@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package seeds_test
+
+import (
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/seeds"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/test"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+var _ = Describe("Seeds Component", func() {
+	var (
+		fs            afero.Afero
+		ocmComponents *ocmcomponents.Components
+	)
+
+	newOptions := func(ocmComponents *ocmcomponents.Components, config string) components.Options {
+		return test.NewOptions(fs, ocmComponents, seeds.ComponentName, config)
+	}
+
+	generate := func(opts components.Options, additionalComponents ...components.Interface) error {
+		return test.Generate(opts, append([]components.Interface{seeds.NewComponent()}, additionalComponents...)...)
+	}
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+
+		ocmComponents = test.OCMFixture{
+			Descriptors: map[ocmcomponents.ComponentReference]string{
+				ocmcomponents.ComponentReferenceFromNameAndVersion(test.RootComponent.Name, test.RootComponent.Version): test.Descriptor(test.RootComponent.Name, test.RootComponent.Version,
+					[]string{test.ComponentReference("gardener", ocmcomponents.ComponentNameGardener, "v1.128.3")}),
+				"github.com/gardener/gardener:v1.128.3": test.Descriptor(ocmcomponents.ComponentNameGardener, "v1.128.3", nil,
+					`{"name": "gardenlet", "version": "v1.128.3", "type": "helmChart/v1", "relation": "external",
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/charts/gardener/gardenlet:v1.128.3"}}`,
+					`{"name": "gardenlet", "version": "v1.128.3", "type": "ociImage", "relation": "external",
+  "labels": [{"name": "imagevector.gardener.cloud/name", "value": "gardenlet"}],
+  "access": {"type": "ociRegistry", "imageReference": "registry.example.com/gardener/gardenlet:v1.128.3"}}`),
+			},
+		}.Resolve()
+	})
+
+	It("should generate a Gardenlet resource per seed into the base and the seed settings into the landscape", func() {
+		Expect(generate(newOptions(ocmComponents, `{"seeds": [
+  {
+    "name": "aws-eu1",
+    "kubeconfigSecretName": "seed-aws-eu1-kubeconfig",
+    "labels": {"seed.gardener.cloud/purpose": "default"},
+    "spec": {
+      "provider": {"type": "aws", "region": "eu-west-1"},
+      "dns": {"provider": {"type": "aws-route53", "secretRef": {"name": "dns", "namespace": "garden"}}},
+      "ingress": {"domain": "ingress.aws-eu1.example.com", "controller": {"kind": "nginx"}},
+      "networks": {"nodes": "10.0.0.0/16", "pods": "10.1.0.0/16", "services": "10.2.0.0/16"},
+      "backup": {"provider": "aws", "region": "eu-west-1", "credentialsRef": {"apiVersion": "v1", "kind": "Secret", "name": "backup-aws-eu1", "namespace": "garden"}}
+    }
+  },
+  {
+    "name": "runtime",
+    "spec": {"provider": {"type": "gcp", "region": "europe-west1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}
+  }
+]}`))).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/base/components/seeds/aws-eu1/gardenlet.yaml")).To(MatchYAML(`apiVersion: seedmanagement.gardener.cloud/v1alpha1
+kind: Gardenlet
+metadata:
+  name: aws-eu1
+  namespace: garden
+spec:
+  config:
+    apiVersion: gardenlet.config.gardener.cloud/v1alpha1
+    kind: GardenletConfiguration
+  deployment:
+    helm:
+      ociRepository:
+        ref: registry.example.com/charts/gardener/gardenlet:v1.128.3
+    imageVectorOverwrite: |
+      images:
+      - name: gardenlet
+        repository: registry.example.com/gardener/gardenlet
+        tag: v1.128.3
+        version: v1.128.3
+status: {}
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/aws-eu1/resources/seed-patch.yaml")).To(MatchYAML(`apiVersion: seedmanagement.gardener.cloud/v1alpha1
+kind: Gardenlet
+metadata:
+  name: aws-eu1
+  namespace: garden
+spec:
+  config:
+    seedConfig:
+      metadata:
+        labels:
+          seed.gardener.cloud/purpose: default
+        name: aws-eu1
+      spec:
+        backup:
+          credentialsRef:
+            apiVersion: v1
+            kind: Secret
+            name: backup-aws-eu1
+            namespace: garden
+          provider: aws
+          region: eu-west-1
+        dns:
+          provider:
+            secretRef:
+              name: dns
+              namespace: garden
+            type: aws-route53
+        ingress:
+          controller:
+            kind: nginx
+          domain: ingress.aws-eu1.example.com
+        networks:
+          nodes: 10.0.0.0/16
+          pods: 10.1.0.0/16
+          services: 10.2.0.0/16
+        provider:
+          region: eu-west-1
+          type: aws
+  kubeconfigSecretRef:
+    name: seed-aws-eu1-kubeconfig
+`))
+		content, err := fs.ReadFile("/repo/landscape/components/seeds/aws-eu1/flux-kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("name: seed-aws-eu1"))
+		Expect(string(content)).To(ContainSubstring("path: landscape/components/seeds/aws-eu1/resources"))
+		Expect(string(content)).To(ContainSubstring("namespace: garden\n"))
+		Expect(string(content)).To(ContainSubstring(`  kubeConfig:
+    secretRef:
+      key: kubeconfig
+      name: gardener-internal
+`))
+
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/runtime/resources/seed-patch.yaml")).NotTo(ContainSubstring("kubeconfigSecretRef"))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- aws-eu1/flux-kustomization.yaml
+- runtime/flux-kustomization.yaml
+`))
+	})
+
+	It("should scaffold the secrets of the backup and DNS providers of the seeds if secrets are configured", func() {
+		opts := newOptions(ocmComponents, `{"seeds": [
+  {
+    "name": "a",
+    "spec": {
+      "provider": {"type": "aws", "region": "eu-west-1"},
+      "backup": {"provider": "aws", "credentialsRef": {"apiVersion": "v1", "kind": "Secret", "name": "backup-a", "namespace": "garden"}},
+      "dns": {
+        "provider": {"type": "aws-route53", "secretRef": {"name": "dns"}},
+        "defaults": [{"type": "aws-route53", "domain": "example.com", "credentialsRef": {"apiVersion": "v1", "kind": "Secret", "name": "dns", "namespace": "garden"}}]
+      },
+      "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}
+    }
+  },
+  {
+    "name": "b",
+    "spec": {
+      "provider": {"type": "aws", "region": "eu-central-1"},
+      "backup": {"provider": "aws", "credentialsRef": {"apiVersion": "security.gardener.cloud/v1alpha1", "kind": "WorkloadIdentity", "name": "backup-b", "namespace": "garden"}},
+      "dns": {"provider": {"type": "aws-route53", "secretRef": {"name": "dns", "namespace": "garden"}}},
+      "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}
+    }
+  }
+]}`)
+		opts.GetConfig().Secrets = &configv1alpha1.SecretsConfig{AgeRecipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}}
+
+		Expect(generate(opts)).To(Succeed())
+
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/a/resources/secret-backup-a.sops.yaml")).To(HavePrefix(`# Credentials of the aws object store the backups of seed "a" are stored in.
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/a/resources/secret-dns.sops.yaml")).To(HavePrefix(`# Credentials of the DNS provider of seed "a" of type aws-route53.
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/a/resources/kustomization.yaml")).To(ContainSubstring(`resources:
+  - ../../../../../base/components/seeds/a
+  - secret-backup-a.sops.yaml
+  - secret-dns.sops.yaml
+`))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/a/flux-kustomization.yaml")).To(ContainSubstring(`  decryption:
+    provider: sops
+    secretRef:
+      name: sops-age
+`))
+		Expect(fs.Exists("/repo/landscape/components/seeds/b/resources/secret-backup-b.sops.yaml")).To(BeFalse())
+		Expect(fs.Exists("/repo/landscape/components/seeds/b/resources/secret-dns.sops.yaml")).To(BeFalse())
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/b/flux-kustomization.yaml")).NotTo(ContainSubstring("decryption"))
+	})
+
+	It("should make dependents depend on the Flux Kustomizations of all seeds in the garden namespace", func() {
+		Expect(generate(newOptions(ocmComponents, `{"seeds": [
+  {"name": "a", "spec": {"provider": {"type": "aws", "region": "eu-west-1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}},
+  {"name": "b", "spec": {"provider": {"type": "gcp", "region": "europe-west1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}}
+]}`), &dependentComponent{})).To(Succeed())
+
+		content, err := fs.ReadFile("/repo/landscape/components/seeds/dependent/flux-kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		k := &kustomizev1.Kustomization{}
+		Expect(yaml.Unmarshal(content, k)).To(Succeed())
+		Expect(k.Spec.DependsOn).To(Equal([]kustomizev1.DependencyReference{{Name: "seed-a", Namespace: "garden"}, {Name: "seed-b", Namespace: "garden"}}))
+		Expect(fs.ReadFile("/repo/landscape/components/seeds/a/flux-kustomization.yaml")).To(ContainSubstring("  name: seed-a\n  namespace: garden\n"))
+	})
+
+	It("should skip the seeds if the OCM components have not been resolved", func() {
+		Expect(generate(newOptions(nil, `{"seeds": [{"name": "a", "spec": {"provider": {"type": "aws", "region": "eu-west-1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}}]}`))).To(Succeed())
+
+		Expect(fs.DirExists("/repo/base/components/seeds/a")).To(BeFalse())
+		Expect(fs.DirExists("/repo/landscape/components/seeds/a")).To(BeFalse())
+	})
+
+	It("should fail for invalid seed configurations", func() {
+		Expect(generate(newOptions(ocmComponents, `{"seeds": [{"name": "A"}]}`))).To(MatchError(ContainSubstring(`seed 0 of component "seeds" has an invalid name "A"`)))
+		Expect(generate(newOptions(ocmComponents, `{"seeds": [{"name": "a"}]}`))).To(MatchError(ContainSubstring(`seed "a" of component "seeds" is missing spec.networks.pods, spec.networks.services, spec.provider.region, spec.provider.type`)))
+		Expect(generate(newOptions(ocmComponents, `{"seeds": [
+  {"name": "a", "spec": {"provider": {"type": "aws", "region": "eu-west-1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}},
+  {"name": "a", "spec": {"provider": {"type": "aws", "region": "eu-west-1"}, "dns": {}, "networks": {"pods": "10.1.0.0/16", "services": "10.2.0.0/16"}}}
+]}`))).To(MatchError(ContainSubstring(`seed "a" of component "seeds" is configured more than once`)))
+	})
+})
+
+type dependentComponent struct{}
+
+func (c *dependentComponent) Name() string {
+	return "dependent"
+}
+
+func (c *dependentComponent) Dependencies() []string {
+	return []string{seeds.ComponentName}
+}
+
+func (c *dependentComponent) Layer() components.Layer {
+	return components.LayerSeeds
+}
+
+func (c *dependentComponent) GenerateBase(components.Options) error {
+	return nil
+}
+
+func (c *dependentComponent) GenerateLandscape(options components.Options) error {
+	return components.WriteFluxComponent(options, components.ComponentDir(c.Layer(), c.Name()), kustomization.FluxKustomizationOptions{
+		Name:      c.Name(),
+		SourceRef: flux.SourceRef,
+	}, "", nil, nil)
+}
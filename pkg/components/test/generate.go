@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/components"
+	"github.com/gardener/gardener-landscape-kit/pkg/components/flux"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+const (
+	// BaseDir is the base directory the components are generated into.
+	BaseDir = "/repo/base"
+	// LandscapeDir is the landscape directory the components are generated into.
+	LandscapeDir = "/repo/landscape"
+)
+
+// NewOptions returns the options for generating the components into BaseDir and LandscapeDir of the given file system.
+// The given configuration of the named component is added unless it is empty.
+func NewOptions(fs afero.Afero, ocmComponents *ocmcomponents.Components, componentName, config string) components.Options {
+	var componentConfigs []configv1alpha1.ComponentConfig
+	if config != "" {
+		componentConfigs = append(componentConfigs, configv1alpha1.ComponentConfig{Name: componentName, Config: &runtime.RawExtension{Raw: []byte(config)}})
+	}
+	return components.NewOptions(BaseDir, nil, LandscapeDir, &configv1alpha1.LandscapeKitConfiguration{
+		Components: componentConfigs,
+	}, ocmComponents, fs, logr.Discard())
+}
+
+// Generate generates the given components together with the flux component, which all components depend on.
+func Generate(opts components.Options, componentsToGenerate ...components.Interface) error {
+	registry := components.NewRegistry(1, false)
+	for _, component := range append([]components.Interface{flux.NewComponent()}, componentsToGenerate...) {
+		if err := registry.RegisterComponent(component); err != nil {
+			return err
+		}
+	}
+	return registry.Generate(opts)
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm"
+	ocmcomponents "github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+// RootComponent is the default root component of the OCM components resolved by an OCMFixture.
+var RootComponent = configv1alpha1.OCMComponent{Name: "example.com/root", Version: "1.0.0"}
+
+// TestdataRootComponent is the root component of the OCM component test data in pkg/ocm/components/testdata.
+var TestdataRootComponent = configv1alpha1.OCMComponent{Name: "example.com/kubernetes-root-example", Version: "0.1499.0"}
+
+// OCMFixture contains the OCM components, which are resolved into an in-memory file system the way resolve-ocm-components does.
+type OCMFixture struct {
+	// RootComponent is the root component of the resolved components. RootComponent is used if it is not set.
+	RootComponent *configv1alpha1.OCMComponent
+	// Descriptors are the component descriptors in JSON.
+	Descriptors map[ocmcomponents.ComponentReference]string
+	// Resources are the dumped resources in JSON, which provide the blobs of the helm chart image maps.
+	Resources map[ocmcomponents.ComponentReference]string
+	// OriginalRefs is whether the image vectors contain the original image references.
+	OriginalRefs bool
+}
+
+// TestdataFixture returns the fixture of the OCM component test data in the given directory.
+// The blobs of the helm chart image maps, which are not part of the component descriptors, are faked.
+func TestdataFixture(dir string) OCMFixture {
+	return OCMFixture{
+		RootComponent: &TestdataRootComponent,
+		Descriptors:   DescriptorFiles(path.Join(dir, "*.json")),
+		Resources: map[ocmcomponents.ComponentReference]string{
+			"github.com/gardener/gardener:v1.128.3":                             testdataImageMapResources,
+			"github.com/gardener/etcd-druid:v0.32.0":                            testdataImageMapResources,
+			"github.com/gardener/gardener-extension-shoot-cert-service:v1.53.0": testdataImageMapResources,
+		},
+	}
+}
+
+// Resolve writes the descriptors and resources of the fixture together with the image vectors calculated from them, and loads the
+// resolved components like the generate command does.
+func (f OCMFixture) Resolve() *ocmcomponents.Components {
+	rootComponent := RootComponent
+	if f.RootComponent != nil {
+		rootComponent = *f.RootComponent
+	}
+
+	var (
+		fs  = afero.Afero{Fs: afero.NewMemMapFs()}
+		dir = ocm.ResolvedComponentsDir("/landscape", rootComponent)
+	)
+	for cref, descriptor := range f.Descriptors {
+		ExpectWithOffset(1, fs.WriteFile(cref.ToFilename(path.Join(dir, "descriptors")), []byte(descriptor), 0600)).To(Succeed())
+	}
+	for cref, resources := range f.Resources {
+		ExpectWithOffset(1, fs.WriteFile(cref.ToFilename(path.Join(dir, "resources")), []byte(resources), 0600)).To(Succeed())
+	}
+
+	ocmComponents, err := ocm.LoadResolvedComponents(dir, rootComponent, fs)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, ocm.WriteImageVectors(dir, ocmComponents, f.OriginalRefs, fs)).To(Succeed())
+
+	ocmComponents, err = ocm.LoadResolvedComponents(dir, rootComponent, fs)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return ocmComponents
+}
+
+// Descriptor returns a component descriptor in JSON, which references the given components and contains the given resources.
+// The component references and resources are given in JSON, see ComponentReference.
+func Descriptor(name, version string, componentReferences []string, resources ...string) string {
+	return fmt.Sprintf(`{"meta": {"schemaVersion": "v2"}, "component": {
+  "name": %q, "version": %q, "provider": "test", "repositoryContexts": [], "sources": [],
+  "componentReferences": [%s],
+  "resources": [%s]}}`, name, version, strings.Join(componentReferences, ","), strings.Join(resources, ","))
+}
+
+// ComponentReference returns a reference to the given component in JSON.
+func ComponentReference(name, componentName, version string) string {
+	return fmt.Sprintf(`{"name": %q, "componentName": %q, "version": %q}`, name, componentName, version)
+}
+
+// DescriptorFiles returns the component descriptors in the files matching the given pattern, e.g. the test data of the OCM components.
+func DescriptorFiles(pattern string) map[ocmcomponents.ComponentReference]string {
+	filePaths, err := filepath.Glob(pattern)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	descriptors := make(map[ocmcomponents.ComponentReference]string, len(filePaths))
+	for _, filePath := range filePaths {
+		data, err := os.ReadFile(filePath)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+		var descriptor struct {
+			Component struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"component"`
+		}
+		ExpectWithOffset(1, json.Unmarshal(data, &descriptor)).To(Succeed(), filePath)
+		descriptors[ocmcomponents.ComponentReferenceFromNameAndVersion(descriptor.Component.Name, descriptor.Component.Version)] = string(data)
+	}
+	return descriptors
+}
+
+// testdataImageMapResources provides fake blobs for all helm chart image maps in the test data.
+const testdataImageMapResources = `{
+  "resources": [
+    {"name": "resource-manager", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "gardenlet", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "operator", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "controlplane", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "etcd-druid", "version": "v0.32.0", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "shoot-cert-service", "version": "v1.53.0", "type": "helmchart-imagemap", "value": "fake content"}
+  ]
+}`
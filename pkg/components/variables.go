@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// variableUsageRegex matches usages of variables substituted by Flux, i.e. ${name}, ${name:=default} or ${name:-default}.
+// Escaped usages ($${name}) are not substituted and are matched with a leading '$' to skip them.
+var variableUsageRegex = regexp.MustCompile(`\$?\$\{([_a-zA-Z][_a-zA-Z0-9]*)(:?[=-][^}]*)?\}`)
+
+// findUndefinedVariables returns the variables used without default in the manifests of the components directories of the base, group
+// and landscape directories, which are not defined in the configuration. The result maps the variable names to the files using them.
+func findUndefinedVariables(opts Options) (map[string][]string, error) {
+	var (
+		fs        = opts.GetFilesystem()
+		defined   = opts.GetConfig().Variables
+		undefined = make(map[string][]string)
+	)
+
+	for _, dir := range slices.Concat([]string{opts.GetBaseDir()}, opts.GetGroupDirs(), []string{opts.GetLandscapeDir()}) {
+		componentsDir := path.Join(dir, DirName)
+		exists, err := fs.DirExists(componentsDir)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		if err := fs.Walk(componentsDir, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			content, err := fs.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			for _, match := range variableUsageRegex.FindAllSubmatch(content, -1) {
+				name := string(match[1])
+				if match[0][1] == '$' || len(match[2]) > 0 {
+					// Escaped usage or usage with default value.
+					continue
+				}
+				if _, ok := defined[name]; !ok && !slices.Contains(undefined[name], filePath) {
+					undefined[name] = append(undefined[name], filePath)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return undefined, nil
+}
+
+// reportUndefinedVariables logs all variables used in the generated manifests that are not defined in the configuration.
+func reportUndefinedVariables(opts Options) error {
+	undefined, err := findUndefinedVariables(opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(undefined)) {
+		opts.GetLogger().Info("Warning: Variable is used but not defined in the configuration, it will not be substituted", "variable", name, "files", undefined[name])
+	}
+	return nil
+}
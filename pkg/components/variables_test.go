@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package components
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+)
+
+var _ = Describe("Variables", func() {
+	var (
+		fs   afero.Afero
+		opts Options
+	)
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		opts = NewOptions("/repo/base", nil, "/repo/landscape", &configv1alpha1.LandscapeKitConfiguration{
+			Variables: map[string]string{"domain": "example.com"},
+		}, nil, fs, logr.Discard())
+	})
+
+	Describe("#findUndefinedVariables", func() {
+		It("should not report anything if there are no components", func() {
+			Expect(findUndefinedVariables(opts)).To(BeEmpty())
+		})
+
+		It("should report undefined variables used without default", func() {
+			Expect(fs.WriteFile("/repo/base/components/foo/deployment.yaml", []byte(`host: api.${domain}
+region: ${region}
+provider: ${provider:=aws}
+zone: ${zone:-a}
+escaped: $${escaped}
+`), 0600)).To(Succeed())
+			Expect(fs.WriteFile("/repo/landscape/components/foo/resources/patch.yaml", []byte(`region: ${region}
+name: ${landscape_name}
+`), 0600)).To(Succeed())
+			Expect(fs.WriteFile("/repo/landscape/flux/ignored.yaml", []byte(`name: ${ignored}`), 0600)).To(Succeed())
+
+			Expect(findUndefinedVariables(opts)).To(Equal(map[string][]string{
+				"region":         {"/repo/base/components/foo/deployment.yaml", "/repo/landscape/components/foo/resources/patch.yaml"},
+				"landscape_name": {"/repo/landscape/components/foo/resources/patch.yaml"},
+			}))
+		})
+	})
+})
@@ -13,10 +13,12 @@ import (
 
 	"github.com/gardener/gardener/pkg/utils/imagevector"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/utils/ptr"
 	descriptorruntime "ocm.software/open-component-model/bindings/go/descriptor/runtime"
 	descriptorv2 "ocm.software/open-component-model/bindings/go/descriptor/v2"
 	accessv1 "ocm.software/open-component-model/bindings/go/oci/spec/access/v1"
+	"sigs.k8s.io/yaml"
 
 	ocmimagevector "github.com/gardener/gardener-landscape-kit/pkg/ocm/imagevector"
 	"github.com/gardener/gardener-landscape-kit/pkg/ocm/ociaccess"
@@ -54,6 +56,8 @@ type Components struct {
 	dependencies map[ComponentReference][]Dependency
 	mappedImages map[ComponentReference][]*ocmimagevector.ExtendedImageSource
 	resources    map[ComponentReference][]Resource
+	// resolvedImageVectors are the image vectors written when resolving the components.
+	resolvedImageVectors map[ComponentReference][]imagevector.ImageSource
 
 	kubernetesComponent *ComponentReference
 }
@@ -86,6 +90,8 @@ func NewComponents() *Components {
 		dependencies: make(map[ComponentReference][]Dependency),
 		mappedImages: make(map[ComponentReference][]*ocmimagevector.ExtendedImageSource),
 		resources:    make(map[ComponentReference][]Resource),
+
+		resolvedImageVectors: make(map[ComponentReference][]imagevector.ImageSource),
 	}
 }
 
@@ -298,6 +304,49 @@ func (c *Components) GetResources(cref ComponentReference) []Resource {
 	return c.resources[cref]
 }
 
+// GetResource returns the resource of the given component with the given name and type, or nil if there is none.
+func (c *Components) GetResource(cref ComponentReference, name, resourceType string) *Resource {
+	for _, resource := range c.GetResources(cref) {
+		if resource.Name == name && resource.Type == resourceType {
+			return &resource
+		}
+	}
+	return nil
+}
+
+// FindResource returns the resource with the given name and type of the component with the given name and the reference of the component.
+// It fails if the component is not contained, is contained in multiple versions or does not contain the resource.
+func (c *Components) FindResource(componentName, resourceName, resourceType string) (ComponentReference, *Resource, error) {
+	cref, err := c.GetComponentReferenceByName(componentName)
+	if err != nil {
+		return "", nil, err
+	}
+	if cref == "" {
+		return "", nil, fmt.Errorf("component %s is not contained in the resolved components", componentName)
+	}
+	resource := c.GetResource(cref, resourceName, resourceType)
+	if resource == nil {
+		return "", nil, fmt.Errorf("component %s does not contain the %s resource %s", cref, resourceType, resourceName)
+	}
+	return cref, resource, nil
+}
+
+// GetComponentReferenceByName returns the reference of the component with the given name, or an empty reference if there is none.
+// It fails if the component is contained in multiple versions.
+func (c *Components) GetComponentReferenceByName(name string) (ComponentReference, error) {
+	var found ComponentReference
+	for _, cref := range c.GetSortedComponents() {
+		if !cref.HasName(name) {
+			continue
+		}
+		if found != "" {
+			return "", fmt.Errorf("component %s is contained in multiple versions: %s, %s", name, found, cref)
+		}
+		found = cref
+	}
+	return found, nil
+}
+
 // DumpComponentRefListAsYAML dumps all components and their versions as a YAML string.
 func (c *Components) DumpComponentRefListAsYAML() (string, error) {
 	var (
@@ -438,6 +487,77 @@ func (c *Components) GetImageVector(cref ComponentReference, originalRef bool) (
 	return images, nil
 }
 
+// SetResolvedImageVector sets the image vector of the given component reference as written when resolving the components.
+func (c *Components) SetResolvedImageVector(cref ComponentReference, images []imagevector.ImageSource) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.resolvedImageVectors[cref] = images
+}
+
+// GetImageVectorOverwrite returns the resolved image vector of the given component reference serialized as image vector
+// overwrite, which is passed to the Gardener components. It returns an empty string if the component has no images.
+func (c *Components) GetImageVectorOverwrite(cref ComponentReference) (string, error) {
+	c.lock.Lock()
+	images := c.resolvedImageVectors[cref]
+	c.lock.Unlock()
+
+	if len(images) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(ocmimagevector.ImageVectorOutput{Images: images})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetKubernetesVersions returns the Kubernetes versions of the kube-apiserver images contained in the Kubernetes component
+// in ascending order.
+func (c *Components) GetKubernetesVersions() ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cref, err := c.getKubernetesComponentRef()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*utilversion.Version
+	for _, dep := range c.dependencies[cref] {
+		if dep.ComponentReference != cref {
+			continue
+		}
+		for _, img := range dep.ImageVector {
+			if img.EffectiveResourceName() != ResourceNameKubeAPIServer || img.Version == nil {
+				continue
+			}
+			version, err := utilversion.ParseSemantic(*img.Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version of resource %s of component %s: %w", ResourceNameKubeAPIServer, cref, err)
+			}
+			if !slices.ContainsFunc(versions, version.EqualTo) {
+				versions = append(versions, version)
+			}
+		}
+	}
+	slices.SortFunc(versions, func(a, b *utilversion.Version) int {
+		switch {
+		case a.LessThan(b):
+			return -1
+		case b.LessThan(a):
+			return 1
+		}
+		return 0
+	})
+
+	result := make([]string, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, version.String())
+	}
+	return result, nil
+}
+
 func (c *Components) getKubernetesComponentRef() (ComponentReference, error) {
 	if c.kubernetesComponent != nil {
 		return *c.kubernetesComponent, nil
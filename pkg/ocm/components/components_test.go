@@ -52,6 +52,45 @@ var _ = Describe("Components", func() {
 		c = components.NewComponents()
 	})
 
+	It("should find components by name", func() {
+		loadWithDep(1, refRoot)
+		Expect(c.GetComponentReferenceByName("github.com/gardener/gardener")).To(Equal(refGardener))
+		Expect(c.GetComponentReferenceByName("github.com/gardener/unknown")).To(BeEmpty())
+
+		c.AddComponentDependency(refRoot, components.Dependency{ComponentReference: "github.com/gardener/gardener:v1.129.0"})
+		_, err := c.GetComponentReferenceByName("github.com/gardener/gardener")
+		Expect(err).To(MatchError("component github.com/gardener/gardener is contained in multiple versions: github.com/gardener/gardener:v1.128.3, github.com/gardener/gardener:v1.129.0"))
+	})
+
+	It("should find resources by name and type", func() {
+		loadWithDep(0, refGardener)
+		Expect(c.GetResource(refGardener, "operator", components.ResourceTypeHelmChart)).To(Equal(&components.Resource{
+			Name:    "operator",
+			Version: "v1.128.3",
+			Type:    components.ResourceTypeHelmChart,
+			Value:   "registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/charts/gardener/operator:v1.128.3@sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b",
+		}))
+		Expect(c.GetResource(refGardener, "operator", components.ResourceTypeOCIImage)).To(HaveField("Type", components.ResourceTypeOCIImage))
+		Expect(c.GetResource(refGardener, "unknown", components.ResourceTypeHelmChart)).To(BeNil())
+
+		cref, resource, err := c.FindResource(components.ComponentNameGardener, "operator", components.ResourceTypeHelmChart)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cref).To(Equal(refGardener))
+		Expect(resource.Name).To(Equal("operator"))
+		_, _, err = c.FindResource(components.ComponentNameGardener, "unknown", components.ResourceTypeHelmChart)
+		Expect(err).To(MatchError("component github.com/gardener/gardener:v1.128.3 does not contain the helmChart/v1 resource unknown"))
+		_, _, err = c.FindResource("github.com/gardener/unknown", "operator", components.ResourceTypeHelmChart)
+		Expect(err).To(MatchError("component github.com/gardener/unknown is not contained in the resolved components"))
+	})
+
+	It("should return the Kubernetes versions of the Kubernetes component", func() {
+		_, err := c.GetKubernetesVersions()
+		Expect(err).To(MatchError("could not determine kubernetes component reference"))
+
+		loadWithDep(0, refRoot)
+		Expect(c.GetKubernetesVersions()).To(Equal([]string{"1.31.10", "1.32.6", "1.32.8", "1.33.3", "1.33.4"}))
+	})
+
 	It("should produce correct image vector for shoot-cert-service", func() {
 		loadWithDep(1, refShootCertService)
 		Expect(c.ComponentsCount()).To(Equal(1))
@@ -113,6 +152,21 @@ var _ = Describe("Components", func() {
 		))
 	})
 
+	It("should serialize the resolved image vector as image vector overwrite", func() {
+		loadWithDep(1, refShootCertService)
+		images, err := c.GetImageVector(refShootCertService, true)
+		Expect(err).NotTo(HaveOccurred())
+		c.SetResolvedImageVector(refShootCertService, images)
+
+		Expect(c.GetImageVectorOverwrite(refShootCertService)).To(MatchYAML(`images:
+- name: cert-management
+  repository: europe-docker.pkg.dev/gardener-project/releases/cert-controller-manager
+  tag: v0.17.7
+  version: v0.17.7
+`))
+		Expect(c.GetImageVectorOverwrite(refRoot)).To(BeEmpty())
+	})
+
 	It("should produce correct image vector for gardener/gardener for rewritten images in OCM components", func() {
 		loadWithDep(3, refRoot)
 		Expect(c.ComponentsCount()).To(Equal(23))
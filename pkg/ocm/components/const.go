@@ -5,6 +5,14 @@
 package components
 
 const (
+	// ComponentNameGardener is the name of the Gardener component containing the charts of the Gardener components, e.g. the gardener-operator.
+	ComponentNameGardener = "github.com/gardener/gardener"
+	// ComponentNameDashboard is the name of the component of the Gardener dashboard.
+	ComponentNameDashboard = "github.com/gardener/dashboard"
+
+	// ResourceNameKubeAPIServer is the name of the kube-apiserver image resources of the Kubernetes component.
+	ResourceNameKubeAPIServer = "kube-apiserver"
+
 	// LabelImageVectorImages is the label name for the images mapping
 	LabelImageVectorImages = "imagevector.gardener.cloud/images"
 	// LabelImageVectorApplication is the label name to mark a component as a source for images of an application, e.g. "kubernetes"
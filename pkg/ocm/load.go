@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/gardener/gardener/pkg/utils/imagevector"
+	"github.com/spf13/afero"
+	descriptorruntime "ocm.software/open-component-model/bindings/go/descriptor/runtime"
+	descriptorv2 "ocm.software/open-component-model/bindings/go/descriptor/v2"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+	ocmimagevector "github.com/gardener/gardener-landscape-kit/pkg/ocm/imagevector"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm/ociaccess"
+)
+
+const (
+	// DirName is the name of the directory in the landscape directory that contains the resolved OCM components.
+	DirName = "ocm"
+
+	descriptorsDirName  = "descriptors"
+	resourcesDirName    = "resources"
+	imageVectorsDirName = "imagevectors"
+)
+
+// ResolvedComponentsDir returns the directory containing the resolved OCM components of the given root component.
+func ResolvedComponentsDir(landscapeDir string, rootComponent configv1alpha1.OCMComponent) string {
+	return path.Join(landscapeDir, DirName, rootComponent.Name, rootComponent.Version)
+}
+
+// LoadResolvedComponents loads the components previously written by ResolveOCMComponents into the given directory.
+// The component graph is rebuilt from the dumped component descriptors starting at the root component.
+// It returns nil if the components have not been resolved yet.
+func LoadResolvedComponents(dir string, rootComponent configv1alpha1.OCMComponent, fs afero.Afero) (*components.Components, error) {
+	descriptorDir := path.Join(dir, descriptorsDirName)
+	if exists, err := fs.DirExists(descriptorDir); err != nil || !exists {
+		return nil, err
+	}
+
+	var (
+		result = components.NewComponents()
+		queue  = []components.ComponentReference{components.ComponentReferenceFromNameAndVersion(rootComponent.Name, rootComponent.Version)}
+	)
+	for len(queue) > 0 {
+		cref := queue[0]
+		queue = queue[1:]
+
+		descriptor, err := loadDescriptor(descriptorDir, cref, fs)
+		if err != nil {
+			return nil, err
+		}
+		blobs, err := loadLocalBlobs(path.Join(dir, resourcesDirName), cref, fs)
+		if err != nil {
+			return nil, err
+		}
+		newComponents, err := result.AddComponentDependencies(descriptor, blobs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add component %s: %w", cref, err)
+		}
+		queue = append(queue, newComponents...)
+	}
+
+	for _, cref := range result.GetSortedComponents() {
+		images, err := loadImageVector(path.Join(dir, imageVectorsDirName), cref, fs)
+		if err != nil {
+			return nil, err
+		}
+		result.SetResolvedImageVector(cref, images)
+	}
+
+	return result, nil
+}
+
+// WriteImageVectors writes the image vectors of all components into the given directory.
+// If originalRefs is true, the original image references are used if available.
+func WriteImageVectors(dir string, c *components.Components, originalRefs bool, fs afero.Afero) error {
+	imageVectorDir := path.Join(dir, imageVectorsDirName)
+	if err := fs.MkdirAll(imageVectorDir, 0700); err != nil {
+		return fmt.Errorf("failed to create image vector directory %s: %w", imageVectorDir, err)
+	}
+
+	for _, cref := range c.GetSortedComponents() {
+		images, err := c.GetImageVector(cref, originalRefs)
+		if err != nil {
+			return fmt.Errorf("failed get image vector for component %s: %w", cref, err)
+		}
+		if len(images) == 0 {
+			continue
+		}
+		data, err := json.MarshalIndent(ocmimagevector.ImageVectorOutput{Images: images}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal image vector for component %s: %w", cref, err)
+		}
+		if err := fs.WriteFile(cref.ToFilename(imageVectorDir), data, 0600); err != nil {
+			return fmt.Errorf("failed to write image vector for component %s: %w", cref, err)
+		}
+	}
+	return nil
+}
+
+// loadImageVector loads the image vector of a component as written by WriteImageVectors.
+func loadImageVector(dir string, cref components.ComponentReference, fs afero.Afero) ([]imagevector.ImageSource, error) {
+	data, err := fs.ReadFile(cref.ToFilename(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read image vector of %s: %w", cref, err)
+	}
+	var output ocmimagevector.ImageVectorOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal image vector of %s: %w", cref, err)
+	}
+	return output.Images, nil
+}
+
+func loadDescriptor(dir string, cref components.ComponentReference, fs afero.Afero) (*descriptorruntime.Descriptor, error) {
+	filename := cref.ToFilename(dir)
+	data, err := fs.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read component descriptor of %s: %w", cref, err)
+	}
+	dv2 := &descriptorv2.Descriptor{}
+	if err := json.Unmarshal(data, dv2); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal component descriptor %s: %w", filename, err)
+	}
+	return descriptorruntime.ConvertFromV2(dv2)
+}
+
+// loadLocalBlobs restores the local blobs of a component from its resources file.
+// Only the helm chart image maps are dumped together with their blob contents.
+func loadLocalBlobs(dir string, cref components.ComponentReference, fs afero.Afero) (components.Blobs, error) {
+	data, err := fs.ReadFile(cref.ToFilename(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read resources of %s: %w", cref, err)
+	}
+	var output components.ResourcesOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resources of %s: %w", cref, err)
+	}
+
+	var blobs components.Blobs
+	for _, res := range output.Resources {
+		if res.Type != components.ResourceTypeHelmChartImageMap {
+			continue
+		}
+		if blobs == nil {
+			blobs = components.Blobs{}
+		}
+		blobs[ociaccess.NameVersionType{Name: res.Name, Version: res.Version, Type: res.Type}] = []byte(res.Value)
+	}
+	return blobs, nil
+}
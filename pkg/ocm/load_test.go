@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocm_test
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm"
+	"github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
+)
+
+var _ = Describe("Load", func() {
+	var (
+		rootComponent = configv1alpha1.OCMComponent{Name: "example.com/kubernetes-root-example", Version: "0.1499.0"}
+
+		fs  afero.Afero
+		dir string
+	)
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		dir = ocm.ResolvedComponentsDir("/landscape", rootComponent)
+	})
+
+	// writeDescriptors writes the component descriptors of the test data and the blobs of their helm chart image maps.
+	writeDescriptors := func() {
+		descriptors, err := filepath.Glob("components/testdata/*.json")
+		Expect(err).NotTo(HaveOccurred())
+		for _, descriptor := range descriptors {
+			data, err := os.ReadFile(descriptor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fs.WriteFile(path.Join(dir, "descriptors", filepath.Base(descriptor)), data, 0600)).To(Succeed())
+		}
+		for _, cref := range []components.ComponentReference{
+			"github.com/gardener/gardener:v1.128.3",
+			"github.com/gardener/etcd-druid:v0.32.0",
+			"github.com/gardener/gardener-extension-shoot-cert-service:v1.53.0",
+		} {
+			Expect(fs.WriteFile(cref.ToFilename(path.Join(dir, "resources")), []byte(imageMapResources), 0600)).To(Succeed())
+		}
+	}
+
+	Describe("#LoadResolvedComponents", func() {
+		It("should return nil if the components have not been resolved", func() {
+			Expect(ocm.LoadResolvedComponents(dir, rootComponent, fs)).To(BeNil())
+		})
+
+		It("should load the component graph from the dumped component descriptors", func() {
+			writeDescriptors()
+
+			c, err := ocm.LoadResolvedComponents(dir, rootComponent, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.ComponentsCount()).To(Equal(23))
+			Expect(c.GetRootComponents()).To(ConsistOf(components.ComponentReference("example.com/kubernetes-root-example:0.1499.0")))
+			Expect(c.GetResources("github.com/gardener/gardener:v1.128.3")).To(ContainElement(components.Resource{
+				Name:    "operator",
+				Version: "v1.128.3",
+				Type:    components.ResourceTypeHelmChart,
+				Value:   "registry.example.com/path/to/repo/europe-docker_pkg_dev/gardener-project/releases/charts/gardener/operator:v1.128.3@sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b",
+			}))
+		})
+
+		It("should load the image vectors written when resolving the components", func() {
+			writeDescriptors()
+			Expect(fs.WriteFile(path.Join(dir, "imagevectors", "github.com_gardener_gardener-extension-shoot-cert-service-v1.53.0.json"), []byte(`{"images": [
+  {"name": "cert-management", "repository": "europe-docker.pkg.dev/gardener-project/releases/cert-controller-manager", "tag": "v0.17.7"}
+]}`), 0600)).To(Succeed())
+
+			c, err := ocm.LoadResolvedComponents(dir, rootComponent, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.GetImageVectorOverwrite("github.com/gardener/gardener-extension-shoot-cert-service:v1.53.0")).To(MatchYAML(`images:
+- name: cert-management
+  repository: europe-docker.pkg.dev/gardener-project/releases/cert-controller-manager
+  tag: v0.17.7
+`))
+			Expect(c.GetImageVectorOverwrite("github.com/gardener/gardener:v1.128.3")).To(BeEmpty())
+		})
+	})
+
+	Describe("#WriteImageVectors", func() {
+		It("should write the image vectors of all components with images", func() {
+			writeDescriptors()
+			c, err := ocm.LoadResolvedComponents(dir, rootComponent, fs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ocm.WriteImageVectors(dir, c, true, fs)).To(Succeed())
+
+			Expect(fs.ReadFile(path.Join(dir, "imagevectors", "github.com_gardener_gardener-extension-shoot-cert-service-v1.53.0.json"))).To(MatchJSON(`{"images": [
+  {"name": "cert-management", "repository": "europe-docker.pkg.dev/gardener-project/releases/cert-controller-manager", "tag": "v0.17.7", "version": "v0.17.7"}
+]}`))
+		})
+	})
+})
+
+// imageMapResources provides fake blobs for all helm chart image maps in the test data.
+const imageMapResources = `{
+  "resources": [
+    {"name": "resource-manager", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "gardenlet", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "operator", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "controlplane", "version": "v1.128.3", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "etcd-druid", "version": "v0.32.0", "type": "helmchart-imagemap", "value": "fake content"},
+    {"name": "shoot-cert-service", "version": "v1.53.0", "type": "helmchart-imagemap", "value": "fake content"}
+  ]
+}`
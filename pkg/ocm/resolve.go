@@ -11,13 +11,12 @@ import (
 	"os"
 	"path"
 
-	"github.com/gardener/gardener/pkg/utils/imagevector"
 	"github.com/go-logr/logr"
+	"github.com/spf13/afero"
 	"ocm.software/open-component-model/bindings/go/descriptor/runtime"
 
 	configv1alpha1 "github.com/gardener/gardener-landscape-kit/pkg/apis/config/v1alpha1"
 	"github.com/gardener/gardener-landscape-kit/pkg/ocm/components"
-	ocmimagevector "github.com/gardener/gardener-landscape-kit/pkg/ocm/imagevector"
 	"github.com/gardener/gardener-landscape-kit/pkg/ocm/ociaccess"
 )
 
@@ -88,7 +87,7 @@ func (r *ocmComponentsResolver) printRepositories() {
 }
 
 func (r *ocmComponentsResolver) ensureOutputDirectories() error {
-	descriptorDir := path.Join(r.outputDir, "descriptors")
+	descriptorDir := path.Join(r.outputDir, descriptorsDirName)
 	if err := os.MkdirAll(descriptorDir, 0700); err != nil {
 		return fmt.Errorf("failed to create output directory %s: %w", descriptorDir, err)
 	}
@@ -115,7 +114,7 @@ func (r *ocmComponentsResolver) walkComponents(ctx context.Context) error {
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal json: %w", err)
 		}
-		filename := cref.ToFilename(path.Join(r.outputDir, "descriptors"))
+		filename := cref.ToFilename(path.Join(r.outputDir, descriptorsDirName))
 		if err := os.WriteFile(filename, data, 0600); err != nil {
 			return nil, fmt.Errorf("failed to write file %s: %w", filename, err)
 		}
@@ -134,26 +133,12 @@ func (r *ocmComponentsResolver) walkComponents(ctx context.Context) error {
 }
 
 func (r *ocmComponentsResolver) writeAllImageVectors() error {
-	imagevectorDir := path.Join(r.outputDir, "imagevectors")
-	r.log.Info("Writing image vectors to directory", "dir", imagevectorDir)
-	for _, cref := range r.components.GetSortedComponents() {
-		images, err := r.components.GetImageVector(cref, r.cfg.OriginalRefs)
-		if len(images) == 0 {
-			continue
-		}
-		r.log.Info("Write image vector", "component", cref, "imageCount", len(images))
-		if err != nil {
-			return fmt.Errorf("failed get image vector for component %s: %w", cref, err)
-		}
-		if err := writeImageVector(imagevectorDir, cref, images); err != nil {
-			return fmt.Errorf("failed to write image vector for component %s: %w", cref, err)
-		}
-	}
-	return nil
+	r.log.Info("Writing image vectors to directory", "dir", path.Join(r.outputDir, imageVectorsDirName))
+	return WriteImageVectors(r.outputDir, r.components, r.cfg.OriginalRefs, afero.Afero{Fs: afero.NewOsFs()})
 }
 
 func (r *ocmComponentsResolver) writeComponentResources() error {
-	resourcesDir := path.Join(r.outputDir, "resources")
+	resourcesDir := path.Join(r.outputDir, resourcesDirName)
 	r.log.Info("Writing component resources to directory", "dir", resourcesDir)
 	for _, cref := range r.components.GetSortedComponents() {
 		resources := r.components.GetResources(cref)
@@ -181,15 +166,6 @@ func (r *ocmComponentsResolver) writeComponentList() error {
 	return nil
 }
 
-func writeImageVector(outputDir string, cref components.ComponentReference, images []imagevector.ImageSource) error {
-	if len(images) == 0 {
-		return nil
-	}
-	return writeObject(outputDir, cref, ocmimagevector.ImageVectorOutput{
-		Images: images,
-	})
-}
-
 func writeResources(outputDir string, cref components.ComponentReference, resources []components.Resource) error {
 	if len(resources) == 0 {
 		return nil
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// ChecksumsFileName is the name of the file within the GLK system directory that contains the checksums of the generated files.
+const ChecksumsFileName = "checksums.yaml"
+
+// Checksums contains the checksums of the generated files of a directory, keyed by their path relative to the directory.
+type Checksums map[string]FileChecksums
+
+// FileChecksums contains the checksums of a generated file and its default at the end of the last generation.
+type FileChecksums struct {
+	// File is the checksum of the generated file. It is empty if the file has been deleted by the user.
+	File string `json:"file,omitempty"`
+	// Default is the checksum of the default of the generated file.
+	Default string `json:"default"`
+}
+
+// ChecksumsReport lists the generated files of a directory that have been changed since the last generation.
+type ChecksumsReport struct {
+	// EditedFiles are the paths of the generated files that have been edited outside of the landscape kit.
+	EditedFiles []string
+	// TamperedDefaults are the paths of the defaults that have been changed outside of the landscape kit.
+	// Merging with a tampered default would silently drop or revert modifications.
+	TamperedDefaults []string
+}
+
+// WriteChecksums computes the checksums of all generated files of the given directory and writes them into its GLK system directory.
+func WriteChecksums(dir string, fs afero.Afero) error {
+	checksums, err := computeChecksums(dir, fs)
+	if err != nil || checksums == nil {
+		return err
+	}
+	return writeChecksums(dir, checksums, fs)
+}
+
+func writeChecksums(dir string, checksums Checksums, fs afero.Afero) error {
+	content, err := yaml.Marshal(checksums)
+	if err != nil {
+		return err
+	}
+	return WriteFileToFilesystem(content, path.Join(dir, GLKSystemDirName, ChecksumsFileName), true, fs)
+}
+
+// readChecksums returns the checksums recorded by the last generation, or nil if there are none.
+func readChecksums(dir string, fs afero.Afero) (Checksums, error) {
+	content, err := fs.ReadFile(path.Join(dir, GLKSystemDirName, ChecksumsFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checksums := Checksums{}
+	if err := yaml.Unmarshal(content, &checksums); err != nil {
+		return nil, fmt.Errorf("failed to read checksums of %s: %w", dir, err)
+	}
+	return checksums, nil
+}
+
+// UpdateChecksums records the checksums of the given generated files of the given directory, identified by their path relative to it.
+// The recorded checksums of all other files are kept, so that their changes are still detected by the next generation.
+func UpdateChecksums(dir string, fs afero.Afero, relativePaths ...string) error {
+	checksums, err := readChecksums(dir, fs)
+	if err != nil || checksums == nil {
+		return err
+	}
+	for _, relativePath := range relativePaths {
+		var fileChecksums FileChecksums
+		if fileChecksums.Default, err = checksumOf(path.Join(dir, GLKSystemDirName, DefaultDirName, relativePath), fs); err != nil {
+			return err
+		}
+		if fileChecksums.File, err = checksumOf(path.Join(dir, relativePath), fs); err != nil {
+			return err
+		}
+		checksums[relativePath] = fileChecksums
+	}
+	return writeChecksums(dir, checksums, fs)
+}
+
+// VerifyChecksums compares the generated files of the given directory with the checksums recorded by the last generation.
+// Files without recorded checksums, e.g. generated by an older version, and defaults that have been removed are not reported.
+func VerifyChecksums(dir string, fs afero.Afero) (*ChecksumsReport, error) {
+	report := &ChecksumsReport{}
+
+	recorded, err := readChecksums(dir, fs)
+	if err != nil || recorded == nil {
+		return report, err
+	}
+
+	current, err := computeChecksums(dir, fs)
+	if err != nil {
+		return nil, err
+	}
+	for relativePath, checksums := range current {
+		previous, ok := recorded[relativePath]
+		if !ok {
+			continue
+		}
+		if checksums.Default != previous.Default {
+			report.TamperedDefaults = append(report.TamperedDefaults, path.Join(dir, GLKSystemDirName, DefaultDirName, relativePath))
+		}
+		if checksums.File != previous.File {
+			report.EditedFiles = append(report.EditedFiles, path.Join(dir, relativePath))
+		}
+	}
+	slices.Sort(report.EditedFiles)
+	slices.Sort(report.TamperedDefaults)
+	return report, nil
+}
+
+// computeChecksums returns the checksums of all files of the given directory that have a default. It returns nil if there are no defaults.
+func computeChecksums(dir string, fs afero.Afero) (Checksums, error) {
+	defaultsDir := path.Join(dir, GLKSystemDirName, DefaultDirName)
+	if exists, err := fs.DirExists(defaultsDir); err != nil || !exists {
+		return nil, err
+	}
+
+	checksums := Checksums{}
+	if err := fs.Walk(defaultsDir, func(filePathDefault string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		relativePath, _ := strings.CutPrefix(filepath.ToSlash(filePathDefault), defaultsDir+"/")
+		var fileChecksums FileChecksums
+		if fileChecksums.Default, err = checksumOf(filePathDefault, fs); err != nil {
+			return err
+		}
+		if fileChecksums.File, err = checksumOf(path.Join(dir, relativePath), fs); err != nil {
+			return err
+		}
+		checksums[relativePath] = fileChecksums
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// checksumOf returns the SHA-256 checksum of the given file, or an empty string if it does not exist.
+func checksumOf(filePath string, fs afero.Afero) (string, error) {
+	content, err := fs.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
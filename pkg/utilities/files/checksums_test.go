@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
+var _ = Describe("Checksums", func() {
+	var fs afero.Afero
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+
+		Expect(files.WriteObjectsToFilesystem(map[string][]byte{
+			"a.yaml": []byte("key: a\n"),
+			"b.yaml": []byte("key: b\n"),
+		}, "/landscape", "component", fs)).To(Succeed())
+	})
+
+	Describe("#VerifyChecksums", func() {
+		It("should not report anything if no checksums have been recorded", func() {
+			Expect(fs.WriteFile("/landscape/component/a.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+
+			Expect(files.VerifyChecksums("/landscape", fs)).To(Equal(&files.ChecksumsReport{}))
+		})
+
+		It("should not report anything if nothing has changed", func() {
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+
+			Expect(files.VerifyChecksums("/landscape", fs)).To(Equal(&files.ChecksumsReport{}))
+		})
+
+		It("should report edited and deleted files as well as tampered defaults", func() {
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+			Expect(fs.WriteFile("/landscape/component/a.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+			Expect(fs.Remove("/landscape/component/b.yaml")).To(Succeed())
+			Expect(fs.WriteFile("/landscape/.glk/defaults/component/b.yaml", []byte("key: tampered\n"), 0600)).To(Succeed())
+
+			Expect(files.VerifyChecksums("/landscape", fs)).To(Equal(&files.ChecksumsReport{
+				EditedFiles:      []string{"/landscape/component/a.yaml", "/landscape/component/b.yaml"},
+				TamperedDefaults: []string{"/landscape/.glk/defaults/component/b.yaml"},
+			}))
+		})
+
+		It("should not report removed defaults", func() {
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+			Expect(fs.Remove("/landscape/.glk/defaults/component/a.yaml")).To(Succeed())
+
+			Expect(files.VerifyChecksums("/landscape", fs)).To(Equal(&files.ChecksumsReport{}))
+		})
+	})
+
+	Describe("#WriteChecksums", func() {
+		It("should write the same content for the same files", func() {
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+			content, err := fs.ReadFile("/landscape/.glk/checksums.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(HavePrefix("component/a.yaml:\n"))
+
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+			Expect(fs.ReadFile("/landscape/.glk/checksums.yaml")).To(Equal(content))
+		})
+	})
+
+	Describe("#UpdateChecksums", func() {
+		It("should only update the checksums of the given files", func() {
+			Expect(files.WriteChecksums("/landscape", fs)).To(Succeed())
+			Expect(fs.WriteFile("/landscape/component/a.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+			Expect(files.WriteObjectsToFilesystem(map[string][]byte{"b.yaml": []byte("key: new\n")}, "/landscape", "component", fs)).To(Succeed())
+
+			Expect(files.UpdateChecksums("/landscape", fs, "component/b.yaml")).To(Succeed())
+
+			Expect(files.VerifyChecksums("/landscape", fs)).To(Equal(&files.ChecksumsReport{
+				EditedFiles: []string{"/landscape/component/a.yaml"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+// FileLocksCount returns the number of file locks currently held or waited for.
+func FileLocksCount() int {
+	fileLocks.mutex.Lock()
+	defer fileLocks.mutex.Unlock()
+	return len(fileLocks.locks)
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// FileLayout defines how multi-document manifests are written to the filesystem.
+type FileLayout string
+
+const (
+	// FileLayoutBundle writes multi-document manifests as they are generated.
+	FileLayoutBundle FileLayout = "Bundle"
+	// FileLayoutSplit writes each object of multi-document manifests into its own file named by its kind and name.
+	FileLayoutSplit FileLayout = "Split"
+)
+
+var (
+	documentSeparatorRegex = regexp.MustCompile(`(?m)^---[ \t]*$`)
+	invalidFileNameRegex   = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// ApplyFileLayout returns the objects to write into the given directory according to the given layout.
+// Files previously written into the directory with the other layout are migrated, i.e. the files of the new layout are seeded from
+// them including their defaults, so that modifications are kept by the subsequent three-way merge. The files of the previous layout are
+// not removed, they are reported as orphaned files afterwards.
+func ApplyFileLayout(objects map[string][]byte, baseDir, filePathDir string, layout FileLayout, fs afero.Afero) (map[string][]byte, error) {
+	if layout != FileLayoutSplit {
+		for fileName, content := range objects {
+			parts := splitManifest(content)
+			if len(parts) <= 1 {
+				continue
+			}
+			if err := seedBundle(fileName, parts, baseDir, filePathDir, fs); err != nil {
+				return nil, err
+			}
+		}
+		return objects, nil
+	}
+
+	result := make(map[string][]byte, len(objects))
+	for _, fileName := range slices.Sorted(maps.Keys(objects)) {
+		parts := splitManifest(objects[fileName])
+		if len(parts) <= 1 {
+			result[fileName] = objects[fileName]
+			continue
+		}
+		for _, part := range parts {
+			if _, ok := objects[part.fileName]; ok {
+				return nil, fmt.Errorf("object %s of %s conflicts with file %s", part.key, fileName, part.fileName)
+			}
+			result[part.fileName] = part.content
+		}
+		if err := seedParts(fileName, parts, baseDir, filePathDir, fs); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type manifestPart struct {
+	key      string
+	fileName string
+	content  []byte
+}
+
+// splitManifest splits the given manifest into its objects. Each object gets a file name consisting of its kind and name, the namespace
+// is only added to disambiguate objects with the same kind and name.
+// Content that does not consist of Kubernetes objects is not split.
+func splitManifest(content []byte) []manifestPart {
+	var parts []manifestPart
+	for _, document := range documentSeparatorRegex.Split(string(content), -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+
+		var object struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil || object.Kind == "" || object.Metadata.Name == "" {
+			return nil
+		}
+
+		parts = append(parts, manifestPart{
+			key:      object.Kind + "/" + object.Metadata.Namespace + "/" + object.Metadata.Name,
+			fileName: fileNameFor(object.Kind, object.Metadata.Name),
+			content:  []byte(strings.TrimLeft(document, "\n")),
+		})
+	}
+
+	fileNames := make(map[string]int, len(parts))
+	for _, part := range parts {
+		fileNames[part.fileName]++
+	}
+	for i, part := range parts {
+		if fileNames[part.fileName] > 1 {
+			kind, name, _ := strings.Cut(part.key, "/")
+			namespace, name, _ := strings.Cut(name, "/")
+			parts[i].fileName = fileNameFor(kind, namespace, name)
+		}
+	}
+	return parts
+}
+
+func fileNameFor(segments ...string) string {
+	var nonEmpty []string
+	for _, segment := range segments {
+		if segment != "" {
+			nonEmpty = append(nonEmpty, strings.Trim(invalidFileNameRegex.ReplaceAllString(strings.ToLower(segment), "-"), "-"))
+		}
+	}
+	return strings.Join(nonEmpty, "-") + ".yaml"
+}
+
+// seedParts seeds the files of the split layout from the bundled file, unless they have been written before.
+func seedParts(bundleFileName string, parts []manifestPart, baseDir, filePathDir string, fs afero.Afero) error {
+	bundle, bundleDefault, err := readWithDefault(baseDir, path.Join(filePathDir, bundleFileName), fs)
+	if err != nil || (bundle == nil && bundleDefault == nil) {
+		return err
+	}
+	bundleParts, bundleDefaultParts := partsByKey(bundle), partsByKey(bundleDefault)
+
+	for _, part := range parts {
+		if err := seedFile(baseDir, path.Join(filePathDir, part.fileName), bundleParts[part.key], bundleDefaultParts[part.key], fs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedBundle seeds the file of the bundled layout from the split files, unless it has been written before.
+func seedBundle(bundleFileName string, parts []manifestPart, baseDir, filePathDir string, fs afero.Afero) error {
+	var bundle, bundleDefault []byte
+	for _, part := range parts {
+		current, defaultContent, err := readWithDefault(baseDir, path.Join(filePathDir, part.fileName), fs)
+		if err != nil {
+			return err
+		}
+		bundle = appendDocument(bundle, current)
+		bundleDefault = appendDocument(bundleDefault, defaultContent)
+	}
+	return seedFile(baseDir, path.Join(filePathDir, bundleFileName), bundle, bundleDefault, fs)
+}
+
+// seedFile writes the given content and default of a file, if neither the file nor its default exist yet.
+// If only the default is given, the file is treated like a file deleted by the user.
+func seedFile(baseDir, filePath string, content, defaultContent []byte, fs afero.Afero) error {
+	if defaultContent == nil {
+		return nil
+	}
+	current, existingDefault, err := readWithDefault(baseDir, filePath, fs)
+	if err != nil || current != nil || existingDefault != nil {
+		return err
+	}
+
+	if content != nil {
+		if err := WriteFileToFilesystem(content, path.Join(baseDir, filePath), false, fs); err != nil {
+			return err
+		}
+	}
+	return WriteFileToFilesystem(defaultContent, path.Join(baseDir, GLKSystemDirName, DefaultDirName, filePath), false, fs)
+}
+
+func readWithDefault(baseDir, filePath string, fs afero.Afero) ([]byte, []byte, error) {
+	current, err := readIfExists(path.Join(baseDir, filePath), fs)
+	if err != nil {
+		return nil, nil, err
+	}
+	defaultContent, err := readIfExists(path.Join(baseDir, GLKSystemDirName, DefaultDirName, filePath), fs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, defaultContent, nil
+}
+
+func readIfExists(filePath string, fs afero.Afero) ([]byte, error) {
+	content, err := fs.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func partsByKey(content []byte) map[string][]byte {
+	result := make(map[string][]byte)
+	for _, part := range splitManifest(content) {
+		result[part.key] = part.content
+	}
+	return result
+}
+
+func appendDocument(bundle, document []byte) []byte {
+	if document == nil {
+		return bundle
+	}
+	if len(bundle) > 0 {
+		if !bytes.HasSuffix(bundle, []byte("\n")) {
+			bundle = append(bundle, '\n')
+		}
+		bundle = append(bundle, []byte("---\n")...)
+	}
+	return append(bundle, document...)
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
+var _ = Describe("Layout", func() {
+	const (
+		configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: garden
+data:
+  key: value
+`
+		secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  namespace: garden
+`
+	)
+
+	var (
+		fs      afero.Afero
+		objects map[string][]byte
+	)
+
+	BeforeEach(func() {
+		fs = afero.Afero{Fs: afero.NewMemMapFs()}
+		objects = map[string][]byte{
+			"bundle.yaml": []byte("---\n" + configMap + "---\n" + secret),
+			"single.yaml": []byte(configMap),
+			"plain.yaml":  []byte("key: value\n---\nother: value\n"),
+		}
+	})
+
+	Describe("#ApplyFileLayout", func() {
+		It("should keep the objects with the bundle layout", func() {
+			Expect(files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutBundle, fs)).To(Equal(objects))
+		})
+
+		It("should split multi-document manifests with the split layout", func() {
+			Expect(files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutSplit, fs)).To(Equal(map[string][]byte{
+				"configmap-config.yaml":   []byte(configMap),
+				"secret-credentials.yaml": []byte(secret),
+				"single.yaml":             []byte(configMap),
+				"plain.yaml":              []byte("key: value\n---\nother: value\n"),
+			}))
+		})
+
+		It("should add the namespace to the file names of objects with the same kind and name", func() {
+			otherConfigMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  namespace: kube-system\n")
+
+			Expect(files.ApplyFileLayout(map[string][]byte{"bundle.yaml": append([]byte(configMap+"---\n"), otherConfigMap...)}, "/landscape", "component", files.FileLayoutSplit, fs)).To(Equal(map[string][]byte{
+				"configmap-garden-config.yaml":      []byte(configMap),
+				"configmap-kube-system-config.yaml": otherConfigMap,
+			}))
+		})
+
+		It("should fail if a split object conflicts with another file", func() {
+			objects["configmap-config.yaml"] = []byte(configMap)
+
+			_, err := files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutSplit, fs)
+			Expect(err).To(MatchError(ContainSubstring("conflicts with file configmap-config.yaml")))
+		})
+
+		It("should migrate a modified bundle to the split layout", func() {
+			Expect(files.WriteObjectsToFilesystem(objects, "/landscape", "component", fs)).To(Succeed())
+			modified := []byte(configMap + "  added: value\n---\n" + secret)
+			Expect(fs.WriteFile("/landscape/component/bundle.yaml", modified, 0600)).To(Succeed())
+
+			split, err := files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutSplit, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files.WriteObjectsToFilesystem(split, "/landscape", "component", fs)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscape/component/configmap-config.yaml")).To(MatchYAML(configMap + "  added: value\n"))
+			Expect(fs.ReadFile("/landscape/.glk/defaults/component/configmap-config.yaml")).To(MatchYAML(configMap))
+			Expect(fs.ReadFile("/landscape/component/secret-credentials.yaml")).To(MatchYAML(secret))
+		})
+
+		It("should not recreate objects removed from the bundle when migrating to the split layout", func() {
+			Expect(files.WriteObjectsToFilesystem(objects, "/landscape", "component", fs)).To(Succeed())
+			Expect(fs.WriteFile("/landscape/component/bundle.yaml", []byte(configMap), 0600)).To(Succeed())
+
+			split, err := files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutSplit, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files.WriteObjectsToFilesystem(split, "/landscape", "component", fs)).To(Succeed())
+
+			Expect(fs.Exists("/landscape/component/configmap-config.yaml")).To(BeTrue())
+			Expect(fs.Exists("/landscape/component/secret-credentials.yaml")).To(BeFalse())
+		})
+
+		It("should migrate modified split files to the bundle layout", func() {
+			split, err := files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutSplit, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files.WriteObjectsToFilesystem(split, "/landscape", "component", fs)).To(Succeed())
+			Expect(fs.WriteFile("/landscape/component/secret-credentials.yaml", []byte(secret+"type: Opaque\n"), 0600)).To(Succeed())
+
+			bundled, err := files.ApplyFileLayout(objects, "/landscape", "component", files.FileLayoutBundle, fs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files.WriteObjectsToFilesystem(bundled, "/landscape", "component", fs)).To(Succeed())
+
+			content, err := fs.ReadFile("/landscape/component/bundle.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(And(
+				ContainSubstring("key: value"),
+				ContainSubstring("type: Opaque"),
+			))
+		})
+	})
+})
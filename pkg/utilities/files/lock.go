@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"path"
+	"sync"
+)
+
+// fileLocks serializes the read-merge-write cycles of the files written to the filesystem.
+// Components may be generated concurrently and write to the same files, e.g. shared kustomization files.
+var fileLocks = &keyedMutex{locks: make(map[string]*refCountedMutex)}
+
+type keyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+// refCountedMutex is a mutex together with the number of callers holding or waiting for it.
+type refCountedMutex struct {
+	mutex sync.Mutex
+	refs  int
+}
+
+// lock locks the mutex for the given file path and returns the function to unlock it again.
+// The mutex is removed once no caller holds or waits for it anymore, so that the locks do not accumulate over time.
+func (k *keyedMutex) lock(filePath string) func() {
+	filePath = path.Clean(filePath)
+
+	k.mutex.Lock()
+	m, ok := k.locks[filePath]
+	if !ok {
+		m = &refCountedMutex{}
+		k.locks[filePath] = m
+	}
+	m.refs++
+	k.mutex.Unlock()
+
+	m.mutex.Lock()
+	return func() {
+		m.mutex.Unlock()
+
+		k.mutex.Lock()
+		defer k.mutex.Unlock()
+		m.refs--
+		if m.refs == 0 {
+			delete(k.locks, filePath)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/meta"
+)
+
+// RecordingFs is a filesystem that records the paths of all files opened for writing.
+type RecordingFs struct {
+	afero.Fs
+
+	mutex   sync.Mutex
+	written sets.Set[string]
+}
+
+// NewRecordingFs returns a new RecordingFs wrapping the given filesystem.
+func NewRecordingFs(fs afero.Fs) *RecordingFs {
+	return &RecordingFs{Fs: fs, written: sets.New[string]()}
+}
+
+// Create creates a file in the filesystem and records its path.
+func (r *RecordingFs) Create(name string) (afero.File, error) {
+	r.record(name)
+	return r.Fs.Create(name)
+}
+
+// OpenFile opens a file using the given flags and mode. The path is recorded if the file is opened for writing.
+func (r *RecordingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE) != 0 {
+		r.record(name)
+	}
+	return r.Fs.OpenFile(name, flag, perm)
+}
+
+// WrittenFiles returns the paths of all files that have been written so far.
+func (r *RecordingFs) WrittenFiles() sets.Set[string] {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.written.Clone()
+}
+
+func (r *RecordingFs) record(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.written.Insert(path.Clean(name))
+}
+
+// OrphanedFile is a generated file whose default is no longer produced.
+type OrphanedFile struct {
+	// Path is the path of the generated file. It might have been deleted by the user already.
+	Path string
+	// DefaultPath is the path of the default of the generated file in the GLK system directory.
+	DefaultPath string
+	// Modified indicates whether the generated file has been modified by the user.
+	Modified bool
+}
+
+// FindOrphanedFiles returns all files generated into the given directory whose defaults are not contained in the given set of written files.
+func FindOrphanedFiles(dir string, written sets.Set[string], fs afero.Afero) ([]OrphanedFile, error) {
+	defaultsDir := path.Join(dir, GLKSystemDirName, DefaultDirName)
+	if exists, err := fs.DirExists(defaultsDir); err != nil || !exists {
+		return nil, err
+	}
+
+	var orphans []OrphanedFile
+	if err := fs.Walk(defaultsDir, func(filePathDefault string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || written.Has(path.Clean(filePathDefault)) {
+			return err
+		}
+
+		relativePath, _ := strings.CutPrefix(filePathDefault, defaultsDir)
+		orphan := OrphanedFile{
+			Path:        path.Join(dir, relativePath),
+			DefaultPath: filePathDefault,
+		}
+		orphan.Modified, err = isModified(orphan, fs)
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, orphan)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// isModified checks whether the generated file differs from its default.
+// A file that has already been deleted by the user is not considered modified.
+func isModified(orphan OrphanedFile, fs afero.Afero) (bool, error) {
+	current, err := fs.ReadFile(orphan.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defaultContent, err := fs.ReadFile(orphan.DefaultPath)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(current, defaultContent) {
+		return false, nil
+	}
+
+	// Manifests are formatted when they are written, compare with the formatted default.
+	// If the default is not a valid manifest, it has been written as is and the file is modified.
+	formattedDefault, err := meta.ThreeWayMergeManifest(nil, defaultContent, nil)
+	return err != nil || !bytes.Equal(current, formattedDefault), nil
+}
+
+// PruneOrphanedFile removes the given orphaned file and its default.
+// Directories that became empty are removed up to the given directory.
+func PruneOrphanedFile(dir string, orphan OrphanedFile, fs afero.Afero) error {
+	for _, filePath := range []string{orphan.Path, orphan.DefaultPath} {
+		unlock := fileLocks.lock(filePath)
+		err := fs.Remove(filePath)
+		unlock()
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := removeEmptyParentDirs(dir, path.Dir(filePath), fs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeEmptyParentDirs(rootDir, dir string, fs afero.Afero) error {
+	rootDir = path.Clean(rootDir)
+	for dir = path.Clean(dir); strings.HasPrefix(dir, rootDir+"/"); dir = path.Dir(dir) {
+		empty, err := fs.IsEmpty(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil && !empty {
+			return nil
+		}
+		if err == nil {
+			if err := fs.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
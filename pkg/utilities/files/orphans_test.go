@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package files_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/files"
+)
+
+var _ = Describe("Orphans", func() {
+	var (
+		recordingFs *files.RecordingFs
+		fs          afero.Afero
+	)
+
+	BeforeEach(func() {
+		recordingFs = files.NewRecordingFs(afero.NewMemMapFs())
+		fs = afero.Afero{Fs: recordingFs}
+
+		Expect(files.WriteObjectsToFilesystem(map[string][]byte{
+			"kept.yaml":     []byte("key: value\n"),
+			"orphan.yaml":   []byte("key:   value\n"),
+			"modified.yaml": []byte("key: value\n"),
+		}, "/landscape", "component", fs)).To(Succeed())
+		Expect(files.WriteObjectsToFilesystem(map[string][]byte{"deleted.yaml": []byte("key: value\n")}, "/landscape", "other", fs)).To(Succeed())
+		Expect(fs.WriteFile("/landscape/component/modified.yaml", []byte("key: changed\n"), 0600)).To(Succeed())
+		Expect(fs.Remove("/landscape/other/deleted.yaml")).To(Succeed())
+	})
+
+	Describe("#RecordingFs", func() {
+		It("should record all written files", func() {
+			Expect(recordingFs.WrittenFiles().UnsortedList()).To(ContainElements(
+				"/landscape/component/kept.yaml",
+				"/landscape/.glk/defaults/component/kept.yaml",
+				"/landscape/component/modified.yaml",
+				"/landscape/.glk/defaults/other/deleted.yaml",
+			))
+		})
+	})
+
+	Describe("#FindOrphanedFiles", func() {
+		It("should not find any orphans if all defaults have been written", func() {
+			Expect(files.FindOrphanedFiles("/landscape", recordingFs.WrittenFiles(), fs)).To(BeEmpty())
+		})
+
+		It("should not find any orphans if there are no defaults", func() {
+			Expect(files.FindOrphanedFiles("/other", sets.New[string](), fs)).To(BeEmpty())
+		})
+
+		It("should find all files whose defaults have not been written", func() {
+			written := sets.New("/landscape/.glk/defaults/component/kept.yaml")
+
+			Expect(files.FindOrphanedFiles("/landscape", written, fs)).To(ConsistOf(
+				files.OrphanedFile{Path: "/landscape/component/modified.yaml", DefaultPath: "/landscape/.glk/defaults/component/modified.yaml", Modified: true},
+				files.OrphanedFile{Path: "/landscape/component/orphan.yaml", DefaultPath: "/landscape/.glk/defaults/component/orphan.yaml"},
+				files.OrphanedFile{Path: "/landscape/other/deleted.yaml", DefaultPath: "/landscape/.glk/defaults/other/deleted.yaml"},
+			))
+		})
+	})
+
+	Describe("#PruneOrphanedFile", func() {
+		It("should remove the file, its default and empty directories", func() {
+			Expect(files.PruneOrphanedFile("/landscape", files.OrphanedFile{Path: "/landscape/component/orphan.yaml", DefaultPath: "/landscape/.glk/defaults/component/orphan.yaml"}, fs)).To(Succeed())
+			Expect(files.PruneOrphanedFile("/landscape", files.OrphanedFile{Path: "/landscape/other/deleted.yaml", DefaultPath: "/landscape/.glk/defaults/other/deleted.yaml"}, fs)).To(Succeed())
+
+			Expect(fs.Exists("/landscape/component/orphan.yaml")).To(BeFalse())
+			Expect(fs.Exists("/landscape/.glk/defaults/component/orphan.yaml")).To(BeFalse())
+			Expect(fs.Exists("/landscape/component/kept.yaml")).To(BeTrue())
+			Expect(fs.DirExists("/landscape/other")).To(BeFalse())
+			Expect(fs.DirExists("/landscape/.glk/defaults/other")).To(BeFalse())
+			Expect(fs.DirExists("/landscape/.glk/defaults")).To(BeTrue())
+		})
+	})
+})
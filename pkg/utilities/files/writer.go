@@ -7,6 +7,8 @@ package files
 import (
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/afero"
 
@@ -24,53 +26,68 @@ const (
 // WriteObjectsToFilesystem writes the given objects to the filesystem at the specified baseDir and filePathDir.
 // If the manifest file already exists, it patches changes from the new default.
 // Additionally, it maintains a default version of the manifest in a separate directory for future diff checks.
+// It is safe to call it concurrently, the merge of each file is performed while holding a lock for its path.
 func WriteObjectsToFilesystem(objects map[string][]byte, baseDir, filePathDir string, fs afero.Afero) error {
 	if err := fs.MkdirAll(path.Join(baseDir, filePathDir), 0700); err != nil {
 		return err
 	}
 
 	for fileName, object := range objects {
-		filePath := path.Join(filePathDir, fileName)
-
-		filePathCurrent := path.Join(baseDir, filePath)
-		currentYaml, err := fs.ReadFile(filePathCurrent)
-		isCurrentNotExistsErr := os.IsNotExist(err)
-		if err != nil && !isCurrentNotExistsErr {
+		if err := writeObjectToFilesystem(object, baseDir, path.Join(filePathDir, fileName), fs); err != nil {
 			return err
 		}
+	}
 
-		filePathDefault := path.Join(baseDir, GLKSystemDirName, DefaultDirName, filePath)
-		oldDefaultYaml, err := fs.ReadFile(filePathDefault)
-		isDefaultNotExistsErr := os.IsNotExist(err)
-		if err != nil && !isDefaultNotExistsErr {
-			return err
-		}
+	return nil
+}
 
-		if !isDefaultNotExistsErr && len(oldDefaultYaml) > 0 && isCurrentNotExistsErr {
-			// File has been deleted by the user. Do not recreate until the default file within the .glk directory is deleted.
-			continue
-		}
+func writeObjectToFilesystem(object []byte, baseDir, filePath string, fs afero.Afero) error {
+	filePathCurrent := path.Join(baseDir, filePath)
+	unlock := fileLocks.lock(filePathCurrent)
+	defer unlock()
 
-		output, err := meta.ThreeWayMergeManifest(oldDefaultYaml, object, currentYaml)
-		if err != nil {
-			return err
-		}
-		// write new manifest
-		if err := WriteFileToFilesystem(output, filePathCurrent, true, fs); err != nil {
-			return err
-		}
-		// write new default
-		if err := WriteFileToFilesystem(object, filePathDefault, true, fs); err != nil {
-			return err
-		}
+	currentYaml, err := fs.ReadFile(filePathCurrent)
+	isCurrentNotExistsErr := os.IsNotExist(err)
+	if err != nil && !isCurrentNotExistsErr {
+		return err
 	}
 
-	return nil
+	filePathDefault := path.Join(baseDir, GLKSystemDirName, DefaultDirName, filePath)
+	oldDefaultYaml, err := fs.ReadFile(filePathDefault)
+	isDefaultNotExistsErr := os.IsNotExist(err)
+	if err != nil && !isDefaultNotExistsErr {
+		return err
+	}
+
+	if !isDefaultNotExistsErr && len(oldDefaultYaml) > 0 && isCurrentNotExistsErr {
+		// File has been deleted by the user. Do not recreate until the default file within the .glk directory is deleted.
+		// The old default is kept as is, but rewritten to mark it as still being generated (see RecordingFs).
+		return writeFile(oldDefaultYaml, filePathDefault, true, fs)
+	}
+
+	output, err := meta.ThreeWayMergeManifest(oldDefaultYaml, object, currentYaml)
+	if err != nil {
+		return err
+	}
+	// write new manifest
+	if err := writeFile(output, filePathCurrent, true, fs); err != nil {
+		return err
+	}
+	// write new default
+	return writeFile(object, filePathDefault, true, fs)
 }
 
 // WriteFileToFilesystem writes the given file to the filesystem at the specified baseDir and filePathDir.
 // If overwriteExisting is false and the file already exists, it does nothing.
+// It is safe to call it concurrently.
 func WriteFileToFilesystem(contents []byte, filePathDir string, overwriteExisting bool, fs afero.Afero) error {
+	unlock := fileLocks.lock(filePathDir)
+	defer unlock()
+
+	return writeFile(contents, filePathDir, overwriteExisting, fs)
+}
+
+func writeFile(contents []byte, filePathDir string, overwriteExisting bool, fs afero.Afero) error {
 	exists, err := fs.Exists(filePathDir)
 	if err != nil {
 		return err
@@ -94,3 +111,18 @@ func ComputeBasePath(baseDir, landscapeDir string) string {
 	}
 	return baseDir
 }
+
+// ComputeRelativeBasePath returns the path of the base directory relative to the given directory within the landscape directory.
+// For same-repo setups, the path is built from the base path determined by ComputeBasePath, so that it stays valid within the repository.
+// Otherwise, the path is computed from the given directories directly.
+func ComputeRelativeBasePath(baseDir, landscapeDir, dirInLandscape string) (string, error) {
+	if basePath := ComputeBasePath(baseDir, landscapeDir); basePath != baseDir {
+		var depth int
+		if dir := path.Clean(dirInLandscape); dir != "." {
+			depth = len(strings.Split(dir, "/"))
+		}
+		return path.Join(strings.Repeat("../", depth+1), basePath), nil
+	}
+	relativePath, err := filepath.Rel(path.Join(landscapeDir, dirInLandscape), baseDir)
+	return filepath.ToSlash(relativePath), err
+}
@@ -7,6 +7,7 @@ package files_test
 import (
 	_ "embed"
 	"strings"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -51,6 +52,18 @@ var _ = Describe("Writer", func() {
 		})
 	})
 
+	Describe("#ComputeRelativeBasePath", func() {
+		It("should compute the relative path for same-repo setups", func() {
+			Expect(files.ComputeRelativeBasePath("/repo/base", "/repo/landscape", "components/foo/resources")).To(Equal("../../../../base"))
+			Expect(files.ComputeRelativeBasePath("/repo/base", "/repo/landscape", "")).To(Equal("../base"))
+		})
+
+		It("should compute the relative path for other setups", func() {
+			Expect(files.ComputeRelativeBasePath("/repo/base", "/repo/landscapes/dev", "components/foo")).To(Equal("../../../../base"))
+			Expect(files.ComputeRelativeBasePath("base", "landscapes/dev", "flux")).To(Equal("../../../base"))
+		})
+	})
+
 	Describe("#WriteObjectsToFilesystem", func() {
 		It("should ensure the directories within the path and write the objects", func() {
 			objects := map[string][]byte{
@@ -73,6 +86,22 @@ var _ = Describe("Writer", func() {
 	})
 
 	Describe("#WriteObjectsToFilesystem", func() {
+		It("should be safe to write the same file concurrently", func() {
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Go(func() {
+					defer GinkgoRecover()
+					Expect(files.WriteObjectsToFilesystem(map[string][]byte{"config.yaml": objYaml}, "/landscape", "manifest", fs)).To(Succeed())
+				})
+			}
+			wg.Wait()
+
+			content, err := fs.ReadFile("/landscape/manifest/config.yaml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(MatchYAML(objYaml))
+			Expect(files.FileLocksCount()).To(BeZero())
+		})
+
 		It("should overwrite the manifest file if no meta file is present yet", func() {
 			Expect(files.WriteObjectsToFilesystem(map[string][]byte{"config.yaml": objYaml}, "/landscape", "manifest", fs)).To(Succeed())
 
@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev2 "github.com/fluxcd/source-controller/api/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+const (
+	// OCIScheme is the URL scheme of Helm charts stored in OCI registries.
+	OCIScheme = "oci://"
+
+	// ChartContentMediaType is the media type of the layer containing the Helm chart in OCI artifacts.
+	ChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// SourceFileName is the name of the manifest file containing the source of a Helm chart.
+	SourceFileName = "source.yaml"
+	// HelmReleaseFileName is the name of the manifest file containing the HelmRelease.
+	HelmReleaseFileName = "helmrelease.yaml"
+	// ValuesPatchFileName is the name of the patch file containing the values of the HelmRelease.
+	ValuesPatchFileName = "values-patch.yaml"
+	// SourcePatchFileName is the name of the patch file containing the repository settings of the source of a Helm chart.
+	SourcePatchFileName = "source-patch.yaml"
+	// HelmReleasePatchFileName is the name of the patch file containing the chart version, the target namespace and the values of the
+	// HelmRelease.
+	HelmReleasePatchFileName = "helmrelease-patch.yaml"
+)
+
+// ChartOptions contains the settings of a Helm chart deployed by Flux.
+type ChartOptions struct {
+	// Name is the name of the chart source and the HelmRelease. It is also used as the release name.
+	Name string
+	// Namespace is the namespace of the chart source and the HelmRelease.
+	Namespace string
+	// Repository is the URL of the chart repository. OCI repositories are prefixed with oci://, all others are treated as HTTP repositories.
+	Repository string
+	// Chart is the name of the chart in the repository. For OCI repositories, it is appended to the repository URL if set.
+	Chart string
+	// Version is the version of the chart.
+	Version string
+	// Digest is the digest of the chart artifact. It is only used for OCI charts and takes precedence over the version.
+	Digest string
+	// TargetNamespace is the namespace the chart is installed into.
+	TargetNamespace string
+	// SecretRefName is the name of the secret in the namespace of the chart source containing the credentials for the chart repository.
+	// OCI registries require a secret of type kubernetes.io/dockerconfigjson, HTTP repositories a username and password.
+	SecretRefName string
+	// Values are the default values of the HelmRelease.
+	Values map[string]any
+}
+
+// IsOCIRepository returns whether the chart is stored in an OCI registry.
+func (o ChartOptions) IsOCIRepository() bool {
+	return strings.HasPrefix(o.Repository, OCIScheme)
+}
+
+// ParseOCIReference splits the given OCI artifact reference in the format "<repository>[:<tag>][@<digest>]" into
+// the repository URL prefixed with oci://, the tag and the digest.
+func ParseOCIReference(ref string) (repository, tag, digest string, err error) {
+	repository, digest, _ = strings.Cut(strings.TrimPrefix(ref, OCIScheme), "@")
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	if repository == "" || (tag == "" && digest == "") {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q, a tag or digest is required", ref)
+	}
+	return OCIScheme + repository, tag, digest, nil
+}
+
+// NewChartManifests returns the manifests of the chart source and the HelmRelease deploying the chart with the given options.
+// OCI charts are pulled via an OCIRepository, all other charts via a HelmRepository.
+func NewChartManifests(opts ChartOptions) (map[string][]byte, error) {
+	source := newChartSource(opts)
+	helmRelease, err := newHelmRelease(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceContent, err := yaml.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	helmReleaseContent, err := yaml.Marshal(helmRelease)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		SourceFileName:      sourceContent,
+		HelmReleaseFileName: helmReleaseContent,
+	}, nil
+}
+
+func newChartSource(opts ChartOptions) any {
+	objectMeta := metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace}
+	interval := metav1.Duration{Duration: kustomization.DefaultFluxInterval}
+	var secretRef *fluxmeta.LocalObjectReference
+	if opts.SecretRefName != "" {
+		secretRef = &fluxmeta.LocalObjectReference{Name: opts.SecretRefName}
+	}
+
+	if opts.IsOCIRepository() {
+		url := opts.Repository
+		if opts.Chart != "" {
+			url = strings.TrimSuffix(url, "/") + "/" + opts.Chart
+		}
+		return &sourcev2.OCIRepository{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: sourcev2.GroupVersion.String(),
+				Kind:       sourcev2.OCIRepositoryKind,
+			},
+			ObjectMeta: objectMeta,
+			Spec: sourcev2.OCIRepositorySpec{
+				URL:       url,
+				Reference: &sourcev2.OCIRepositoryRef{Tag: opts.Version, Digest: opts.Digest},
+				LayerSelector: &sourcev2.OCILayerSelector{
+					MediaType: ChartContentMediaType,
+					Operation: sourcev2.OCILayerCopy,
+				},
+				SecretRef: secretRef,
+				Interval:  interval,
+			},
+		}
+	}
+
+	return &sourcev2.HelmRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev2.GroupVersion.String(),
+			Kind:       sourcev2.HelmRepositoryKind,
+		},
+		ObjectMeta: objectMeta,
+		Spec: sourcev2.HelmRepositorySpec{
+			URL:       opts.Repository,
+			SecretRef: secretRef,
+			Interval:  interval,
+		},
+	}
+}
+
+func newHelmRelease(opts ChartOptions) (*helmv2.HelmRelease, error) {
+	helmRelease := &helmv2.HelmRelease{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: helmv2.GroupVersion.String(),
+			Kind:       helmv2.HelmReleaseKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: helmv2.HelmReleaseSpec{
+			Interval:         metav1.Duration{Duration: kustomization.DefaultFluxInterval},
+			ReleaseName:      opts.Name,
+			TargetNamespace:  opts.TargetNamespace,
+			StorageNamespace: opts.TargetNamespace,
+		},
+	}
+
+	if opts.IsOCIRepository() {
+		helmRelease.Spec.ChartRef = &helmv2.CrossNamespaceSourceReference{
+			Kind:      sourcev2.OCIRepositoryKind,
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		}
+	} else {
+		helmRelease.Spec.Chart = &helmv2.HelmChartTemplate{
+			Spec: helmv2.HelmChartTemplateSpec{
+				Chart:   opts.Chart,
+				Version: opts.Version,
+				SourceRef: helmv2.CrossNamespaceObjectReference{
+					Kind:      sourcev2.HelmRepositoryKind,
+					Name:      opts.Name,
+					Namespace: opts.Namespace,
+				},
+			},
+		}
+	}
+
+	if len(opts.Values) > 0 {
+		values, err := json.Marshal(opts.Values)
+		if err != nil {
+			return nil, err
+		}
+		helmRelease.Spec.Values = &apiextensionsv1.JSON{Raw: values}
+	}
+	return helmRelease, nil
+}
+
+// NewValuesPatch returns a patch setting the given values in the HelmRelease with the given name and namespace.
+func NewValuesPatch(name, namespace string, values map[string]any) ([]byte, error) {
+	if values == nil {
+		values = map[string]any{}
+	}
+	return marshalObject(helmv2.GroupVersion.String(), helmv2.HelmReleaseKind, name, namespace, map[string]any{
+		"values": values,
+	})
+}
+
+// NewChartSkeletonManifests returns the manifests of the chart source and the HelmRelease of the chart with the given options, which
+// do not contain any landscape specific settings. Only the name, the namespace and the kind of the repository are taken from the
+// options, the repository, chart, version, target namespace and values are set by the patches returned by NewChartPatches.
+func NewChartSkeletonManifests(opts ChartOptions) (map[string][]byte, error) {
+	interval := kustomization.DefaultFluxInterval.String()
+
+	sourceSpec := map[string]any{"interval": interval}
+	helmReleaseSpec := map[string]any{
+		"interval":    interval,
+		"releaseName": opts.Name,
+	}
+	sourceRef := map[string]any{"kind": sourceKind(opts), "name": opts.Name, "namespace": opts.Namespace}
+	if opts.IsOCIRepository() {
+		sourceSpec["layerSelector"] = map[string]any{"mediaType": ChartContentMediaType, "operation": sourcev2.OCILayerCopy}
+		helmReleaseSpec["chartRef"] = sourceRef
+	} else {
+		helmReleaseSpec["chart"] = map[string]any{"spec": map[string]any{"sourceRef": sourceRef}}
+	}
+
+	source, err := marshalObject(sourcev2.GroupVersion.String(), sourceKind(opts), opts.Name, opts.Namespace, sourceSpec)
+	if err != nil {
+		return nil, err
+	}
+	helmRelease, err := marshalObject(helmv2.GroupVersion.String(), helmv2.HelmReleaseKind, opts.Name, opts.Namespace, helmReleaseSpec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		SourceFileName:      source,
+		HelmReleaseFileName: helmRelease,
+	}, nil
+}
+
+// NewChartPatches returns the patches setting the repository, chart, version, target namespace and values of the chart with the given
+// options in the manifests returned by NewChartSkeletonManifests.
+func NewChartPatches(opts ChartOptions) (map[string][]byte, error) {
+	values := opts.Values
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	sourceSpec := map[string]any{"url": opts.Repository}
+	helmReleaseSpec := map[string]any{
+		"targetNamespace":  opts.TargetNamespace,
+		"storageNamespace": opts.TargetNamespace,
+		"values":           values,
+	}
+	if opts.IsOCIRepository() {
+		if opts.Chart != "" {
+			sourceSpec["url"] = strings.TrimSuffix(opts.Repository, "/") + "/" + opts.Chart
+		}
+		ref := map[string]any{}
+		if opts.Version != "" {
+			ref["tag"] = opts.Version
+		}
+		if opts.Digest != "" {
+			ref["digest"] = opts.Digest
+		}
+		sourceSpec["ref"] = ref
+	} else {
+		helmReleaseSpec["chart"] = map[string]any{"spec": map[string]any{"chart": opts.Chart, "version": opts.Version}}
+	}
+	if opts.SecretRefName != "" {
+		sourceSpec["secretRef"] = map[string]any{"name": opts.SecretRefName}
+	}
+
+	sourcePatch, err := marshalObject(sourcev2.GroupVersion.String(), sourceKind(opts), opts.Name, opts.Namespace, sourceSpec)
+	if err != nil {
+		return nil, err
+	}
+	helmReleasePatch, err := marshalObject(helmv2.GroupVersion.String(), helmv2.HelmReleaseKind, opts.Name, opts.Namespace, helmReleaseSpec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		SourcePatchFileName:      sourcePatch,
+		HelmReleasePatchFileName: helmReleasePatch,
+	}, nil
+}
+
+func sourceKind(opts ChartOptions) string {
+	if opts.IsOCIRepository() {
+		return sourcev2.OCIRepositoryKind
+	}
+	return sourcev2.HelmRepositoryKind
+}
+
+func marshalObject(apiVersion, kind, name, namespace string, spec map[string]any) ([]byte, error) {
+	return yaml.Marshal(map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	})
+}
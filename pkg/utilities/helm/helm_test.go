@@ -0,0 +1,345 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/helm"
+)
+
+var _ = Describe("Helm", func() {
+	Describe("#NewChartManifests", func() {
+		It("should use an OCIRepository for OCI charts", func() {
+			objects, err := helm.NewChartManifests(helm.ChartOptions{
+				Name:            "cert-manager",
+				Namespace:       "flux-system",
+				Repository:      "oci://quay.io/jetstack/charts",
+				Chart:           "cert-manager",
+				Version:         "v1.16.0",
+				TargetNamespace: "cert-manager",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  interval: 10m0s
+  layerSelector:
+    mediaType: application/vnd.cncf.helm.chart.content.v1.tar+gzip
+    operation: copy
+  ref:
+    tag: v1.16.0
+  url: oci://quay.io/jetstack/charts/cert-manager
+status: {}
+`)))
+			Expect(objects).To(HaveKeyWithValue(helm.HelmReleaseFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  chartRef:
+    kind: OCIRepository
+    name: cert-manager
+    namespace: flux-system
+  interval: 10m0s
+  releaseName: cert-manager
+  storageNamespace: cert-manager
+  targetNamespace: cert-manager
+status: {}
+`)))
+		})
+
+		It("should use a HelmRepository for HTTP charts", func() {
+			objects, err := helm.NewChartManifests(helm.ChartOptions{
+				Name:            "external-dns",
+				Namespace:       "flux-system",
+				Repository:      "https://kubernetes-sigs.github.io/external-dns",
+				Chart:           "external-dns",
+				Version:         "1.15.0",
+				TargetNamespace: "external-dns",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  interval: 10m0s
+  url: https://kubernetes-sigs.github.io/external-dns
+status: {}
+`)))
+			Expect(objects).To(HaveKeyWithValue(helm.HelmReleaseFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  chart:
+    spec:
+      chart: external-dns
+      sourceRef:
+        kind: HelmRepository
+        name: external-dns
+        namespace: flux-system
+      version: 1.15.0
+  interval: 10m0s
+  releaseName: external-dns
+  storageNamespace: external-dns
+  targetNamespace: external-dns
+status: {}
+`)))
+		})
+
+		It("should pin OCI charts to the digest", func() {
+			objects, err := helm.NewChartManifests(helm.ChartOptions{
+				Name:            "operator",
+				Namespace:       "garden",
+				Repository:      "oci://example.com/charts/operator",
+				Version:         "v1.128.3",
+				Digest:          "sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b",
+				TargetNamespace: "garden",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, ContainSubstring(`  ref:
+    digest: sha256:1e544a671edc7e5b72c45f1fce3817a67c4eefa3d709855c119708fb04737f5b
+    tag: v1.128.3
+  url: oci://example.com/charts/operator
+`)))
+		})
+
+		It("should reference the credentials of the chart repository", func() {
+			for _, repository := range []string{"oci://example.com/charts", "https://example.com/charts"} {
+				objects, err := helm.NewChartManifests(helm.ChartOptions{
+					Name:            "operator",
+					Namespace:       "flux-system",
+					Repository:      repository,
+					Chart:           "operator",
+					Version:         "v1.128.3",
+					TargetNamespace: "garden",
+					SecretRefName:   "registry-credentials",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, ContainSubstring(`  secretRef:
+    name: registry-credentials
+`)))
+			}
+		})
+
+		It("should set the default values of the HelmRelease", func() {
+			objects, err := helm.NewChartManifests(helm.ChartOptions{
+				Name:            "operator",
+				Namespace:       "garden",
+				Repository:      "oci://example.com/charts/operator",
+				Version:         "v1.128.3",
+				TargetNamespace: "garden",
+				Values:          map[string]any{"replicaCount": 2},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.HelmReleaseFileName, ContainSubstring(`  values:
+    replicaCount: 2
+`)))
+		})
+	})
+
+	Describe("#ParseOCIReference", func() {
+		DescribeTable("should split the reference",
+			func(ref, expectedRepository, expectedTag, expectedDigest string) {
+				repository, tag, digest, err := helm.ParseOCIReference(ref)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(repository).To(Equal(expectedRepository))
+				Expect(tag).To(Equal(expectedTag))
+				Expect(digest).To(Equal(expectedDigest))
+			},
+			Entry("tag", "example.com/charts/operator:v1.0.0", "oci://example.com/charts/operator", "v1.0.0", ""),
+			Entry("tag and digest", "example.com/charts/operator:v1.0.0@sha256:abc", "oci://example.com/charts/operator", "v1.0.0", "sha256:abc"),
+			Entry("digest", "oci://example.com/charts/operator@sha256:abc", "oci://example.com/charts/operator", "", "sha256:abc"),
+			Entry("registry port", "localhost:5000/charts/operator:v1.0.0", "oci://localhost:5000/charts/operator", "v1.0.0", ""),
+		)
+
+		It("should fail for references without tag and digest", func() {
+			_, _, _, err := helm.ParseOCIReference("localhost:5000/charts/operator")
+			Expect(err).To(MatchError(`invalid OCI reference "localhost:5000/charts/operator", a tag or digest is required`))
+		})
+	})
+
+	Describe("#NewChartSkeletonManifests", func() {
+		It("should only contain the generic settings of OCI charts", func() {
+			objects, err := helm.NewChartSkeletonManifests(helm.ChartOptions{
+				Name:            "cert-manager",
+				Namespace:       "flux-system",
+				Repository:      "oci://quay.io/jetstack/charts",
+				Chart:           "cert-manager",
+				Version:         "v1.16.0",
+				TargetNamespace: "cert-manager",
+				SecretRefName:   "registry-credentials",
+				Values:          map[string]any{"replicaCount": 2},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  interval: 10m0s
+  layerSelector:
+    mediaType: application/vnd.cncf.helm.chart.content.v1.tar+gzip
+    operation: copy
+`)))
+			Expect(objects).To(HaveKeyWithValue(helm.HelmReleaseFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  chartRef:
+    kind: OCIRepository
+    name: cert-manager
+    namespace: flux-system
+  interval: 10m0s
+  releaseName: cert-manager
+`)))
+		})
+
+		It("should only contain the generic settings of HTTP charts", func() {
+			objects, err := helm.NewChartSkeletonManifests(helm.ChartOptions{
+				Name:       "external-dns",
+				Namespace:  "flux-system",
+				Repository: "https://kubernetes-sigs.github.io/external-dns",
+				Chart:      "external-dns",
+				Version:    "1.15.0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(objects).To(HaveKeyWithValue(helm.SourceFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  interval: 10m0s
+`)))
+			Expect(objects).To(HaveKeyWithValue(helm.HelmReleaseFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  chart:
+    spec:
+      sourceRef:
+        kind: HelmRepository
+        name: external-dns
+        namespace: flux-system
+  interval: 10m0s
+  releaseName: external-dns
+`)))
+		})
+	})
+
+	Describe("#NewChartPatches", func() {
+		It("should set the landscape specific settings of OCI charts", func() {
+			patches, err := helm.NewChartPatches(helm.ChartOptions{
+				Name:            "cert-manager",
+				Namespace:       "flux-system",
+				Repository:      "oci://quay.io/jetstack/charts/",
+				Chart:           "cert-manager",
+				Version:         "v1.16.0",
+				Digest:          "sha256:abc",
+				TargetNamespace: "cert-manager",
+				SecretRefName:   "registry-credentials",
+				Values:          map[string]any{"replicaCount": 2},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patches).To(HaveKeyWithValue(helm.SourcePatchFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: OCIRepository
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  ref:
+    digest: sha256:abc
+    tag: v1.16.0
+  secretRef:
+    name: registry-credentials
+  url: oci://quay.io/jetstack/charts/cert-manager
+`)))
+			Expect(patches).To(HaveKeyWithValue(helm.HelmReleasePatchFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  storageNamespace: cert-manager
+  targetNamespace: cert-manager
+  values:
+    replicaCount: 2
+`)))
+		})
+
+		It("should set the landscape specific settings of HTTP charts", func() {
+			patches, err := helm.NewChartPatches(helm.ChartOptions{
+				Name:            "external-dns",
+				Namespace:       "flux-system",
+				Repository:      "https://kubernetes-sigs.github.io/external-dns",
+				Chart:           "external-dns",
+				Version:         "1.15.0",
+				TargetNamespace: "external-dns",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patches).To(HaveKeyWithValue(helm.SourcePatchFileName, MatchYAML(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  url: https://kubernetes-sigs.github.io/external-dns
+`)))
+			Expect(patches).To(HaveKeyWithValue(helm.HelmReleasePatchFileName, MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: external-dns
+  namespace: flux-system
+spec:
+  chart:
+    spec:
+      chart: external-dns
+      version: 1.15.0
+  storageNamespace: external-dns
+  targetNamespace: external-dns
+  values: {}
+`)))
+		})
+	})
+
+	Describe("#NewValuesPatch", func() {
+		It("should set the values of the HelmRelease", func() {
+			Expect(helm.NewValuesPatch("cert-manager", "flux-system", map[string]any{"crds": map[string]any{"enabled": true}})).To(MatchYAML(`apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: cert-manager
+  namespace: flux-system
+spec:
+  values:
+    crds:
+      enabled: true
+`))
+		})
+	})
+})
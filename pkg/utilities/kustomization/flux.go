@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kustomization
+
+import (
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxkustomize "github.com/fluxcd/pkg/apis/kustomize"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultFluxInterval is the default reconciliation interval of Flux Kustomizations.
+	DefaultFluxInterval = 10 * time.Minute
+
+	// VariablesConfigMapName is the name of the ConfigMap containing the landscape-wide variables.
+	// All generated Flux Kustomizations substitute the variables from it. The reference is optional, as the ConfigMap is only generated
+	// into the flux-system and garden namespaces, so that Flux Kustomizations in other namespaces do not fail on the missing ConfigMap.
+	VariablesConfigMapName = "glk-variables"
+
+	// DecryptionProviderSOPS is the provider Flux uses to decrypt SOPS encrypted manifests.
+	DecryptionProviderSOPS = "sops"
+)
+
+// FluxKustomizationOptions contains the settings of the Flux Kustomization managing a component.
+type FluxKustomizationOptions struct {
+	// Name is the name of the Flux Kustomization.
+	Name string
+	// Namespace is the namespace of the Flux Kustomization. It defaults to the namespace of the source reference.
+	Namespace string
+	// SourceRef is the reference to the source containing the manifests.
+	SourceRef kustomizev1.CrossNamespaceSourceReference
+	// Interval is the reconciliation interval. It defaults to DefaultFluxInterval.
+	Interval time.Duration
+	// Timeout is the timeout for apply and health checking operations. If not set, the Flux default is used.
+	Timeout time.Duration
+	// Prune enables the garbage collection of removed resources.
+	Prune bool
+	// Wait instructs Flux to wait for all applied resources to become ready. If set, HealthChecks are ignored.
+	Wait bool
+	// HealthChecks are the resources Flux waits for to become ready.
+	HealthChecks []fluxmeta.NamespacedObjectKindReference
+	// HealthCheckExprs are the CEL expressions evaluating the health of custom resources, which do not report a Ready condition.
+	HealthCheckExprs []fluxkustomize.CustomHealthCheck
+	// DependsOn are the Flux Kustomizations that must be ready before this one is applied.
+	DependsOn []kustomizev1.DependencyReference
+	// TargetNamespace sets or overrides the namespace of all applied resources.
+	TargetNamespace string
+	// KubeConfigSecretRef references the secret containing the kubeconfig of a remote cluster the resources are applied to.
+	// The secret must be in the namespace of the Flux Kustomization. If not set, the resources are applied to the local cluster.
+	KubeConfigSecretRef *fluxmeta.SecretKeyReference
+	// DecryptionSecretName is the name of the secret containing the keys Flux decrypts SOPS encrypted manifests with.
+	// The secret must be in the namespace of the Flux Kustomization. If not set, the manifests are not decrypted.
+	DecryptionSecretName string
+}
+
+// NewFluxKustomization creates a Flux Kustomization with the given options that applies the manifests at the given path of the source.
+// The landscape-wide variables are substituted in the applied manifests if their ConfigMap exists in the namespace of the Flux Kustomization.
+func NewFluxKustomization(opts FluxKustomizationOptions, path string) *kustomizev1.Kustomization {
+	interval := opts.Interval
+	if interval == 0 {
+		interval = DefaultFluxInterval
+	}
+	var timeout *metav1.Duration
+	if opts.Timeout != 0 {
+		timeout = &metav1.Duration{Duration: opts.Timeout}
+	}
+
+	var kubeConfig *fluxmeta.KubeConfigReference
+	if opts.KubeConfigSecretRef != nil {
+		kubeConfig = &fluxmeta.KubeConfigReference{SecretRef: opts.KubeConfigSecretRef}
+	}
+	var decryption *kustomizev1.Decryption
+	if opts.DecryptionSecretName != "" {
+		decryption = &kustomizev1.Decryption{
+			Provider:  DecryptionProviderSOPS,
+			SecretRef: &fluxmeta.LocalObjectReference{Name: opts.DecryptionSecretName},
+		}
+	}
+
+	return &kustomizev1.Kustomization{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kustomizev1.GroupVersion.String(),
+			Kind:       kustomizev1.KustomizationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.namespace(),
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			SourceRef:        opts.SourceRef,
+			Path:             path,
+			Interval:         metav1.Duration{Duration: interval},
+			Timeout:          timeout,
+			Prune:            opts.Prune,
+			Wait:             opts.Wait,
+			HealthChecks:     opts.HealthChecks,
+			HealthCheckExprs: opts.HealthCheckExprs,
+			DependsOn:        opts.DependsOn,
+			TargetNamespace:  opts.TargetNamespace,
+			KubeConfig:       kubeConfig,
+			Decryption:       decryption,
+			PostBuild: &kustomizev1.PostBuild{
+				SubstituteFrom: []kustomizev1.SubstituteReference{{
+					Kind:     "ConfigMap",
+					Name:     VariablesConfigMapName,
+					Optional: true,
+				}},
+			},
+		},
+	}
+}
+
+// DependencyReference returns the reference to the Flux Kustomization created with the options, which is listed in the dependsOn of
+// the Flux Kustomizations depending on it.
+func (opts FluxKustomizationOptions) DependencyReference() kustomizev1.DependencyReference {
+	return kustomizev1.DependencyReference{Name: opts.Name, Namespace: opts.namespace()}
+}
+
+func (opts FluxKustomizationOptions) namespace() string {
+	if opts.Namespace == "" {
+		return opts.SourceRef.Namespace
+	}
+	return opts.Namespace
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kustomization_test
+
+import (
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxkustomize "github.com/fluxcd/pkg/apis/kustomize"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
+)
+
+var _ = Describe("Flux", func() {
+	Describe("#NewFluxKustomization", func() {
+		sourceRef := kustomizev1.CrossNamespaceSourceReference{Kind: "GitRepository", Name: "flux-system", Namespace: "flux-system"}
+
+		It("should default the namespace and interval", func() {
+			k := kustomization.NewFluxKustomization(kustomization.FluxKustomizationOptions{Name: "foo", SourceRef: sourceRef}, "landscape/components/foo/resources")
+
+			Expect(k.Name).To(Equal("foo"))
+			Expect(k.Namespace).To(Equal("flux-system"))
+			Expect(k.Spec.SourceRef).To(Equal(sourceRef))
+			Expect(k.Spec.Path).To(Equal("landscape/components/foo/resources"))
+			Expect(k.Spec.Interval).To(Equal(metav1.Duration{Duration: kustomization.DefaultFluxInterval}))
+			Expect(k.Spec.Timeout).To(BeNil())
+			Expect(k.Spec.Decryption).To(BeNil())
+			Expect(k.Spec.PostBuild.SubstituteFrom).To(ConsistOf(kustomizev1.SubstituteReference{Kind: "ConfigMap", Name: "glk-variables", Optional: true}))
+		})
+
+		It("should not require the variables ConfigMap in namespaces other than flux-system", func() {
+			k := kustomization.NewFluxKustomization(kustomization.FluxKustomizationOptions{Name: "foo", Namespace: "garden", SourceRef: sourceRef}, "foo")
+
+			Expect(k.Namespace).To(Equal("garden"))
+			Expect(k.Spec.PostBuild.SubstituteFrom).To(ConsistOf(kustomizev1.SubstituteReference{Kind: "ConfigMap", Name: "glk-variables", Optional: true}))
+		})
+
+		It("should apply all options", func() {
+			k := kustomization.NewFluxKustomization(kustomization.FluxKustomizationOptions{
+				Name:            "foo",
+				Namespace:       "garden",
+				SourceRef:       sourceRef,
+				Interval:        time.Hour,
+				Timeout:         time.Minute,
+				Prune:           true,
+				Wait:            true,
+				DependsOn:       []kustomizev1.DependencyReference{{Name: "bar"}},
+				TargetNamespace: "target",
+				HealthCheckExprs: []fluxkustomize.CustomHealthCheck{{
+					APIVersion:             "example.com/v1",
+					Kind:                   "Foo",
+					HealthCheckExpressions: fluxkustomize.HealthCheckExpressions{Current: "status.ready"},
+				}},
+				KubeConfigSecretRef:  &fluxmeta.SecretKeyReference{Name: "kubeconfig", Key: "value"},
+				DecryptionSecretName: "sops-age",
+			}, "foo")
+
+			Expect(k.Namespace).To(Equal("garden"))
+			Expect(k.Spec.Interval).To(Equal(metav1.Duration{Duration: time.Hour}))
+			Expect(k.Spec.Timeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+			Expect(k.Spec.Prune).To(BeTrue())
+			Expect(k.Spec.Wait).To(BeTrue())
+			Expect(k.Spec.DependsOn).To(ConsistOf(kustomizev1.DependencyReference{Name: "bar"}))
+			Expect(k.Spec.TargetNamespace).To(Equal("target"))
+			Expect(k.Spec.HealthCheckExprs).To(ConsistOf(HaveField("Kind", "Foo")))
+			Expect(k.Spec.KubeConfig).To(Equal(&fluxmeta.KubeConfigReference{SecretRef: &fluxmeta.SecretKeyReference{Name: "kubeconfig", Key: "value"}}))
+			Expect(k.Spec.Decryption).To(Equal(&kustomizev1.Decryption{Provider: "sops", SecretRef: &fluxmeta.LocalObjectReference{Name: "sops-age"}}))
+		})
+	})
+
+	Describe("#DependencyReference", func() {
+		sourceRef := kustomizev1.CrossNamespaceSourceReference{Kind: "GitRepository", Name: "flux-system", Namespace: "flux-system"}
+
+		It("should reference the Flux Kustomization in the namespace of the source by default", func() {
+			Expect(kustomization.FluxKustomizationOptions{Name: "foo", SourceRef: sourceRef}.DependencyReference()).To(Equal(kustomizev1.DependencyReference{Name: "foo", Namespace: "flux-system"}))
+		})
+
+		It("should reference the Flux Kustomization in the configured namespace", func() {
+			Expect(kustomization.FluxKustomizationOptions{Name: "foo", Namespace: "garden", SourceRef: sourceRef}.DependencyReference()).To(Equal(kustomizev1.DependencyReference{Name: "foo", Namespace: "garden"}))
+		})
+	})
+})
@@ -25,27 +25,44 @@ const (
 	// FluxSystemRepositoryName is the name of the Flux system repository.
 	FluxSystemRepositoryName = "flux-system"
 
+	// FluxSystemNamespaceName is the name of the namespace of the Flux installation, which holds its sources and the Flux Kustomizations.
+	FluxSystemNamespaceName = "flux-system"
+
 	// OverrideDir is the directory referenced by the Flux Kustomization of a component.
 	OverrideDir = "resources"
 )
 
-// NewKustomization creates a Kustomization with the given resources.
-func NewKustomization(resources []string, patches []kustomize.Patch) *kustomize.Kustomization {
+// NewKustomization creates a Kustomization with the given resources, patches and kustomize components.
+func NewKustomization(resources []string, patches []kustomize.Patch, components []string) *kustomize.Kustomization {
 	return &kustomize.Kustomization{
 		TypeMeta: kustomize.TypeMeta{
 			APIVersion: kustomize.KustomizationVersion,
 			Kind:       kustomize.KustomizationKind,
 		},
+		Resources:  resources,
+		Components: components,
+		Patches:    patches,
+	}
+}
+
+// NewComponent creates a kustomize Component with the given resources and patches.
+// Components are reusable pieces of configuration that are only applied if a Kustomization lists them in its components.
+func NewComponent(resources []string, patches []kustomize.Patch) *kustomize.Kustomization {
+	return &kustomize.Kustomization{
+		TypeMeta: kustomize.TypeMeta{
+			APIVersion: kustomize.ComponentVersion,
+			Kind:       kustomize.ComponentKind,
+		},
 		Resources: resources,
 		Patches:   patches,
 	}
 }
 
 // WriteKustomizationComponent writes the objects and a Kustomization file to the fs.
-// The Kustomization file references all other objects.
+// The Kustomization file references all other objects in alphabetical order.
 // The objects map will be modified to include the Kustomization file.
 func WriteKustomizationComponent(objects map[string][]byte, baseDir, componentDir string, fs afero.Afero) error {
-	kustomization := NewKustomization(slices.Collect(maps.Keys(objects)), nil)
+	kustomization := NewKustomization(slices.Sorted(maps.Keys(objects)), nil, nil)
 	content, err := yaml.Marshal(kustomization)
 	if err != nil {
 		return err
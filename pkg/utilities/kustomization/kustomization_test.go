@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kustomize "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
 
 	"github.com/gardener/gardener-landscape-kit/pkg/utilities/kustomization"
@@ -65,5 +66,30 @@ var _ = Describe("Kustomization", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(contents)).To(ContainSubstring("- configmap.yaml"))
 		})
+
+		It("should reference the objects in alphabetical order", func() {
+			objects := map[string][]byte{"c.yaml": objYaml, "a.yaml": objYaml, "b.yaml": objYaml}
+
+			Expect(kustomization.WriteKustomizationComponent(objects, "/landscape", "component", fs)).To(Succeed())
+
+			Expect(fs.ReadFile("/landscape/component/kustomization.yaml")).To(MatchYAML(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+- c.yaml
+`))
+		})
+	})
+
+	Describe("#NewComponent", func() {
+		It("should create a kustomize component", func() {
+			component := kustomization.NewComponent([]string{"configmap.yaml"}, []kustomize.Patch{{Path: "patch.yaml"}})
+
+			Expect(component.APIVersion).To(Equal("kustomize.config.k8s.io/v1alpha1"))
+			Expect(component.Kind).To(Equal("Component"))
+			Expect(component.Resources).To(ConsistOf("configmap.yaml"))
+			Expect(component.Patches).To(ConsistOf(kustomize.Patch{Path: "patch.yaml"}))
+		})
 	})
 })